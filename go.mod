@@ -5,6 +5,7 @@ go 1.21.5
 require (
 	fyne.io/fyne/v2 v2.4.3
 	github.com/faiface/beep v1.1.0
+	golang.org/x/image v0.14.0
 )
 
 require (
@@ -32,7 +33,6 @@ require (
 	github.com/tevino/abool v1.2.0 // indirect
 	github.com/yuin/goldmark v1.5.5 // indirect
 	golang.org/x/exp/shiny v0.0.0-20231226003508-02704c960a9b // indirect
-	golang.org/x/image v0.14.0 // indirect
 	golang.org/x/mobile v0.0.0-20231127183840-76ac6878050a // indirect
 	golang.org/x/net v0.17.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
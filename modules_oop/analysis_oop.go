@@ -0,0 +1,131 @@
+package main
+
+// analysisMCTSConfig is the search run on every position of an analysed game, twice, and
+// after the moves played that it hardly tried.
+var analysisMCTSConfig = MCTSConfig{playouts: 1000, rave: true}
+
+// analysisMistake is the loss from which a move is marked as a mistake.
+const analysisMistake = 0.1
+
+// analysisMinVisits is the number of playouts through a move below which its value is not
+// trusted. The position after such a move is searched on its own instead.
+const analysisMinVisits = 100
+
+// PositionAnalysis is the evaluation of one position of a game, reached by the move of its node.
+type PositionAnalysis struct {
+	node      *GameNode
+	winRate   float64 // Blue's chance to win, draws counting half
+	scoreLead int     // Blue's estimated dots minus red's, see Board.ScoreEstimate
+	best      move    // Move the search prefers here, a pass once the game is over
+	loss      float64 // Chances the move leading here gave away against the best move, for its player
+}
+
+// AnalyzeGame evaluates the positions after each of the given nodes, the root first.
+// After every position it calls progress with the positions analysed so far, so results can
+// be shown while the search goes on. It gives up, returning nil, once stop is closed.
+func AnalyzeGame(tree *GameTree, nodes []*GameNode, size int, bot *MCTSBot, progress func([]PositionAnalysis), stop <-chan struct{}) []PositionAnalysis {
+	positions := make([]PositionAnalysis, 0, len(nodes))
+	loss := 0.0 // Of the move leading to the position, found while searching the one before
+	for i, node := range nodes {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		var next *GameNode
+		if i+1 < len(nodes) {
+			next = nodes[i+1]
+		}
+		position, nextLoss := analyzePosition(tree.BoardAt(node, size), next, bot)
+		position.node, position.loss = node, loss
+		loss = nextLoss
+
+		positions = append(positions, position)
+		if progress != nil {
+			progress(positions)
+		}
+	}
+	return positions
+}
+
+// analyzePosition searches a position, scoring it exactly once the game is over. It also
+// returns the loss of the next move, if it is played in this position.
+func analyzePosition(board *Board, next *GameNode, bot *MCTSBot) (PositionAnalysis, float64) {
+	blueScore, redScore := board.ScoreEstimate()
+	position := PositionAnalysis{scoreLead: blueScore - redScore}
+
+	color := board.ToMove()
+	position.best = move{color: color, pass: true}
+	if winner, decided := board.DecidedWinner(); decided || board.IsFull() {
+		position.winRate = mctsScore(blue, winner)
+		return position, 0
+	}
+
+	candidates := bot.Candidates(board, color)
+	if len(candidates) == 0 {
+		position.winRate = 0.5
+		return position, 0
+	}
+	best := candidates[0]
+	position.best = move{color: color, x: best.cell.x, y: best.cell.y}
+
+	// The next move is compared with the best one, both leaving the opponent to move
+	loss := 0.0
+	if next != nil && !next.move.pass && next.move.color == color {
+		loss = max(best.winRate-playedWinRate(board, next.move, candidates, bot), 0)
+	}
+
+	// Random playouts favor the player to move, so the values of the position with either
+	// player to move are averaged
+	other := board.Copy()
+	other.Pass(color)
+	position.winRate = (blueWinRate(candidates, color) + blueWinRate(bot.Candidates(other, opponent(color)), opponent(color))) / 2
+	return position, loss
+}
+
+// playedWinRate returns the chances of a move for its player, taken from the search of the
+// position when enough playouts went through it, otherwise from a search of the position after it.
+func playedWinRate(board *Board, m move, candidates []MCTSCandidate, bot *MCTSBot) float64 {
+	for _, candidate := range candidates {
+		if candidate.cell == (point{m.x, m.y}) && candidate.visits >= analysisMinVisits {
+			return candidate.winRate
+		}
+	}
+
+	after := board.Copy()
+	after.Apply(m)
+	if winner, decided := after.DecidedWinner(); decided || after.IsFull() {
+		return mctsScore(m.color, winner)
+	}
+	blueRate := blueWinRate(bot.Candidates(after, opponent(m.color)), opponent(m.color))
+	if m.color == red {
+		return 1 - blueRate
+	}
+	return blueRate
+}
+
+// blueWinRate returns blue's chances in a searched position: the playouts won over all moves,
+// which get closer to the value of the best move as the search spends more playouts on it.
+func blueWinRate(candidates []MCTSCandidate, color cellState) float64 {
+	wins, visits := 0.0, 0
+	for _, candidate := range candidates {
+		wins += candidate.winRate * float64(candidate.visits)
+		visits += candidate.visits
+	}
+	if visits == 0 {
+		return 0.5
+	}
+	if color == red {
+		return 1 - wins/float64(visits)
+	}
+	return wins / float64(visits)
+}
+
+// mistake reports whether the move leading to the position lost much of its player's chances.
+func (p PositionAnalysis) mistake() bool {
+	return p.loss >= analysisMistake
+}
+
+// Additional methods for handling analysis functionalities
+// ...
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"image/color"
+	"math"
+	"sync"
+)
+
+// Colors of the analysis graph.
+var (
+	graphBackground = color.NRGBA{R: 245, G: 245, B: 245, A: 255}
+	graphGuide      = color.NRGBA{R: 190, G: 190, B: 190, A: 255}
+	graphWinRate    = color.NRGBA{B: 255, A: 255}
+	graphScore      = color.NRGBA{R: 120, G: 120, B: 120, A: 255}
+	graphMistake    = color.NRGBA{R: 255, A: 255}
+	graphCurrent    = color.NRGBA{R: 255, G: 165, A: 255}
+)
+
+// AnalysisWindow reviews the variation shown when it was opened: a bot evaluates every
+// position and the window graphs blue's chances and the estimated score, and lists the
+// loss of each move. Clicking the graph or a move shows that position in the game window.
+type AnalysisWindow struct {
+	window     fyne.Window
+	gameWindow *GameWindow
+	tree       *GameTree // Game analysed, the game window may have moved on to another
+	size       int
+	nodes      []*GameNode
+	mu         sync.Mutex         // Guards positions, published by the analysis as it goes
+	positions  []PositionAnalysis // Analysed so far
+	stop       chan struct{}      // Closed to abandon the analysis
+	// UI components
+	graph       *analysisGraph
+	list        *widget.List
+	progress    *widget.ProgressBar
+	statusLabel *widget.Label
+}
+
+func NewAnalysisWindow(app fyne.App, gameWindow *GameWindow) *AnalysisWindow {
+	aw := &AnalysisWindow{
+		window:      app.NewWindow("Go in Go: analysis"),
+		gameWindow:  gameWindow,
+		tree:        gameWindow.gameTree,
+		size:        gameWindow.gridSize,
+		nodes:       gameWindow.gameTree.Variation(),
+		stop:        make(chan struct{}),
+		progress:    widget.NewProgressBar(),
+		statusLabel: widget.NewLabel(""),
+	}
+	aw.graph = newAnalysisGraph(len(aw.nodes), aw.Open)
+	aw.progress.Max = float64(len(aw.nodes))
+	aw.list = aw.createList()
+
+	legend := widget.NewLabel("Blue's chances in blue, estimated score lead in gray, mistakes in red")
+	top := container.NewVBox(legend, aw.graph)
+	aw.window.SetContent(container.NewBorder(top, container.NewVBox(aw.progress, aw.statusLabel), nil, nil, aw.list))
+	aw.window.Resize(fyne.NewSize(520, 600))
+
+	return aw
+}
+
+// Show makes the window visible and starts the analysis.
+func (aw *AnalysisWindow) Show() {
+	aw.window.Show()
+	aw.Update()
+	aw.Analyze()
+}
+
+// Analyze evaluates the positions in the background, showing each one as it is done.
+// The positions are copied before they are shown, the analysis appending to its own slice.
+func (aw *AnalysisWindow) Analyze() {
+	aw.statusLabel.SetText(fmt.Sprintf("Analysing %d positions...", len(aw.nodes)))
+	go func() {
+		bot := NewMCTSBot(analysisMCTSConfig)
+		positions := AnalyzeGame(aw.tree, aw.nodes, aw.size, bot, func(positions []PositionAnalysis) {
+			shown := append([]PositionAnalysis(nil), positions...)
+			aw.mu.Lock()
+			aw.positions = shown
+			aw.mu.Unlock()
+			aw.graph.SetPositions(shown)
+			aw.list.Refresh()
+			aw.progress.SetValue(float64(len(shown)))
+		}, aw.stop)
+		if positions == nil {
+			return // Abandoned
+		}
+
+		mistakes := make(map[cellState]int)
+		for _, position := range positions {
+			if position.mistake() {
+				mistakes[position.node.move.color]++
+			}
+		}
+		aw.statusLabel.SetText(fmt.Sprintf("Done: %d mistakes by blue, %d by red (loss of %.0f%% or more)",
+			mistakes[blue], mistakes[red], analysisMistake*100))
+	}()
+}
+
+// Stop abandons the analysis after the position being searched.
+func (aw *AnalysisWindow) Stop() {
+	select {
+	case <-aw.stop:
+	default:
+		close(aw.stop)
+	}
+}
+
+// analysed returns the positions analysed so far.
+func (aw *AnalysisWindow) analysed() []PositionAnalysis {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+	return aw.positions
+}
+
+// Open shows the position after the given move in the game window.
+func (aw *AnalysisWindow) Open(index int) {
+	gw := aw.gameWindow
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	if gw.gameTree != aw.tree {
+		aw.statusLabel.SetText("The game window shows another game now")
+		return
+	}
+	if index >= len(aw.analysed()) || aw.nodes[index] == gw.gameTree.current {
+		return
+	}
+	gw.replayBar.StopAutoplay()
+	gw.gameTree.GoTo(aw.nodes[index])
+	gw.ReplayGameTree()
+}
+
+// Update marks the position shown in the game window on the graph.
+func (aw *AnalysisWindow) Update() {
+	current := -1
+	if aw.gameWindow.gameTree == aw.tree {
+		for i, node := range aw.nodes {
+			if node == aw.tree.current {
+				current = i
+			}
+		}
+	}
+	aw.graph.SetCurrent(current)
+}
+
+func (aw *AnalysisWindow) createList() *widget.List {
+	list := widget.NewList(
+		func() int {
+			return len(aw.analysed())
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			positions := aw.analysed()
+			if id >= len(positions) {
+				return
+			}
+			position := positions[id]
+			label := item.(*widget.Label)
+
+			text := "Start"
+			if node := position.node; node.parent != nil {
+				text = fmt.Sprintf("%d. %s %s, loss %.0f%%", node.depth(), node.move.color, analysisVertex(node.move, aw.size), position.loss*100)
+			}
+			text += fmt.Sprintf(", blue %.0f%%, lead %+d", position.winRate*100, position.scoreLead)
+			if !position.best.pass {
+				text += ", best next " + analysisVertex(position.best, aw.size)
+			}
+			label.SetText(text)
+
+			label.Importance = widget.MediumImportance
+			if position.mistake() {
+				label.Importance = widget.DangerImportance
+			}
+			label.Refresh()
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		aw.Open(id)
+		list.Unselect(id)
+	}
+	return list
+}
+
+func analysisVertex(m move, size int) string {
+	if m.pass {
+		return "pass"
+	}
+	return formatVertex(point{m.x, m.y}, size)
+}
+
+// analysisGraph plots blue's chances and the estimated score lead along the game. Tapping
+// it picks the nearest move.
+type analysisGraph struct {
+	widget.BaseWidget
+	mu        sync.Mutex // Guards positions and current, set by the analysis and the game window
+	positions []PositionAnalysis
+	total     int // Positions the analysis covers, so the graph fills from the left
+	current   int // Position shown in the game window, -1 if none
+	onTapped  func(index int)
+}
+
+func newAnalysisGraph(total int, onTapped func(index int)) *analysisGraph {
+	g := &analysisGraph{total: total, current: -1, onTapped: onTapped}
+	g.ExtendBaseWidget(g)
+	return g
+}
+
+// SetPositions redraws the graph with the positions analysed so far.
+func (g *analysisGraph) SetPositions(positions []PositionAnalysis) {
+	g.mu.Lock()
+	g.positions = positions
+	g.mu.Unlock()
+	g.Refresh()
+}
+
+// SetCurrent moves the marker of the position shown.
+func (g *analysisGraph) SetCurrent(current int) {
+	g.mu.Lock()
+	g.current = current
+	g.mu.Unlock()
+	g.Refresh()
+}
+
+func (g *analysisGraph) CreateRenderer() fyne.WidgetRenderer {
+	return &analysisGraphRenderer{graph: g}
+}
+
+func (g *analysisGraph) Tapped(event *fyne.PointEvent) {
+	width := g.Size().Width
+	if g.total < 2 || width <= 0 || g.onTapped == nil {
+		return
+	}
+	index := int(math.Round(float64(event.Position.X / width * float32(g.total-1))))
+	g.onTapped(min(max(index, 0), g.total-1))
+}
+
+// x and y place a position and a value between 0 (bottom) and 1 (top) on a graph of the given size.
+func (g *analysisGraph) x(index int, size fyne.Size) float32 {
+	return float32(index) / float32(max(g.total-1, 1)) * size.Width
+}
+
+func (g *analysisGraph) y(value float64, size fyne.Size) float32 {
+	return float32(1-value) * size.Height
+}
+
+// draw creates the objects of the graph for the given size.
+func (g *analysisGraph) draw(size fyne.Size) []fyne.CanvasObject {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	background := canvas.NewRectangle(graphBackground)
+	background.Resize(size)
+	objects := []fyne.CanvasObject{background, g.line(graphGuide, 1, 0, 0.5, g.total-1, 0.5, size)}
+
+	// The score lead is scaled to the largest lead of the game
+	maxLead := 1
+	for _, position := range g.positions {
+		maxLead = max(maxLead, abs(position.scoreLead))
+	}
+	lead := func(position PositionAnalysis) float64 {
+		return 0.5 + float64(position.scoreLead)/float64(2*maxLead)
+	}
+
+	for i := 1; i < len(g.positions); i++ {
+		previous, position := g.positions[i-1], g.positions[i]
+		objects = append(objects,
+			g.line(graphScore, 1, i-1, lead(previous), i, lead(position), size),
+			g.line(graphWinRate, 2, i-1, previous.winRate, i, position.winRate, size),
+		)
+	}
+
+	markerSize := float32(6)
+	for i, position := range g.positions {
+		if !position.mistake() {
+			continue
+		}
+		marker := canvas.NewCircle(graphMistake)
+		marker.Resize(fyne.NewSize(markerSize, markerSize))
+		marker.Move(fyne.NewPos(g.x(i, size)-markerSize/2, g.y(position.winRate, size)-markerSize/2))
+		objects = append(objects, marker)
+	}
+
+	if g.current >= 0 {
+		objects = append(objects, g.line(graphCurrent, 2, g.current, 0, g.current, 1, size))
+	}
+	return objects
+}
+
+func (g *analysisGraph) line(lineColor color.Color, width float32, from int, fromValue float64, to int, toValue float64, size fyne.Size) *canvas.Line {
+	line := canvas.NewLine(lineColor)
+	line.StrokeWidth = width
+	line.Position1 = fyne.NewPos(g.x(from, size), g.y(fromValue, size))
+	line.Position2 = fyne.NewPos(g.x(to, size), g.y(toValue, size))
+	return line
+}
+
+// analysisGraphRenderer redraws the whole graph whenever it changes or is resized.
+type analysisGraphRenderer struct {
+	graph   *analysisGraph
+	objects []fyne.CanvasObject
+}
+
+func (r *analysisGraphRenderer) Layout(size fyne.Size) {
+	r.objects = r.graph.draw(size)
+}
+
+func (r *analysisGraphRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(320, 160)
+}
+
+func (r *analysisGraphRenderer) Refresh() {
+	r.Layout(r.graph.Size())
+	canvas.Refresh(r.graph)
+}
+
+func (r *analysisGraphRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *analysisGraphRenderer) Destroy() {}
+
+// Additional methods for handling analysis window functionalities
+// ...
@@ -9,6 +9,7 @@ import (
 type GameApp struct {
 	app    fyne.App
 	window *GameWindow
+	resume *autosave // Game left unfinished by the last run, if any
 }
 
 // NewGameApp creates and initializes a new GameApp.
@@ -17,16 +18,28 @@ func NewGameApp() *GameApp {
 
 	gameWindow := NewGameWindow(fyneApp)
 
-	return &GameApp{
+	gameApp := &GameApp{
 		app:    fyneApp,
 		window: gameWindow,
 	}
+
+	// Look for a game to resume, a missing or unreadable file just starts a new game
+	if path, err := autosavePath(); err == nil {
+		if save, err := readAutosave(path); err == nil {
+			gameApp.resume = &save
+		}
+	}
+
+	return gameApp
 }
 
 // Run starts the main loop of the GameApp.
 func (app *GameApp) Run() {
 	app.window.Configure()
 	app.window.Show()
+	if app.resume != nil {
+		app.window.OfferResume(*app.resume)
+	}
 	app.app.Run()
 	app.window.Cleanup()
 }
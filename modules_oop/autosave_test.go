@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAutosaveRoundTrip(t *testing.T) {
+	tree := NewGameTree()
+	for _, m := range []move{{color: blue, x: 1, y: 1}, {color: red, x: 2, y: 2}, {color: blue, x: 0, y: 2}} {
+		tree.Play(m)
+	}
+	tree.Back()
+	tree.Play(move{color: blue, x: 2, y: 0}) // A variation, shown when saved
+	info := defaultGameInfo()
+	info.komi = 0.5
+	players := map[cellState]string{blue: humanPlayer, red: strongPlayer}
+	engine := &EngineConfig{Path: "/usr/local/bin/gnugo", Arguments: "--mode gtp", Color: "Blue"}
+
+	save, err := newAutosave(tree, 3, info, 90*time.Second, true, false, players, engine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "go-in-go", "autosave.json")
+	if err := writeAutosave(path, save); err != nil {
+		t.Fatal(err)
+	}
+	read, err := readAutosave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if read.Size != 3 || read.Moves != 3 || read.Finished || read.elapsed() != 90*time.Second ||
+		!read.ShowTerritory || read.ShowLife {
+		t.Errorf("the settings read back differ: %+v", read)
+	}
+	if got := read.players(); !reflect.DeepEqual(got, players) {
+		t.Errorf("got the players %v, want %v", got, players)
+	}
+	if read.Engine == nil || *read.Engine != *engine {
+		t.Errorf("got the engine %+v, want %+v", read.Engine, engine)
+	}
+
+	restored, restoredInfo, err := read.restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restoredInfo.komi != info.komi {
+		t.Errorf("got komi %g, want %g", restoredInfo.komi, info.komi)
+	}
+	if got, want := restored.Moves(), tree.Moves(); !reflect.DeepEqual(got, want) {
+		t.Errorf("resumed at the moves %v, want %v", got, want)
+	}
+	if got, want := restored.MainLine(), tree.MainLine(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got the main line %v, want %v", got, want)
+	}
+}
+
+// TestAutosaveWithoutPlayers checks that saves made before players were kept are played by people.
+func TestAutosaveWithoutPlayers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autosave.json")
+	if err := os.WriteFile(path, []byte(`{"version": 1, "size": 3, "sgf": "(;SZ[3];B[bb])", "moves": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	save, err := readAutosave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := save.players(); got[blue] != humanPlayer || got[red] != humanPlayer || save.Engine != nil {
+		t.Errorf("got the players %v and the engine %+v, want people", got, save.Engine)
+	}
+}
+
+func TestReadAutosaveErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := readAutosave(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("read a missing file")
+	}
+
+	path := filepath.Join(dir, "autosave.json")
+	if err := os.WriteFile(path, []byte(`{"version": 2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readAutosave(path); err == nil {
+		t.Error("read an unsupported version")
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+// enclosedRegion is a connected set of cells not holding dots of the enclosing color.
+type enclosedRegion struct {
+	points     []point
+	empties    []point
+	neighbours map[int]bool // Indexes of the enclosing chains touching the region
+}
+
+// UnconditionalLife runs Benson's algorithm for the given color. It returns the groups
+// that would stay alive in Go even if their owner never answered, and the cells of the
+// regions they enclose, which the opponent could never live in. Dots are never captured
+// here and the opponent may still fill the eyes, after which the group is no longer alive,
+// so this only guides the scoring phase and Score does not count it.
+func (b *Board) UnconditionalLife(color cellState) (alive []Group, territory []point) {
+	chains := b.Groups(color)
+	chainAt := make(map[point]int)
+	for i, chain := range chains {
+		for _, stone := range chain.stones {
+			chainAt[stone] = i
+		}
+	}
+	regions := b.enclosedRegions(color, chainAt)
+	liberties := make([]map[point]bool, len(chains))
+	for i, chain := range chains {
+		liberties[i] = make(map[point]bool, len(chain.liberties))
+		for _, liberty := range chain.liberties {
+			liberties[i][liberty] = true
+		}
+	}
+
+	aliveChains := make(map[int]bool)
+	for i := range chains {
+		aliveChains[i] = true
+	}
+	aliveRegions := make(map[int]bool)
+	for i := range regions {
+		aliveRegions[i] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		// Remove chains with fewer than two vital regions
+		for i := range aliveChains {
+			vital := 0
+			for j := range aliveRegions {
+				if regions[j].neighbours[i] && isVitalRegion(regions[j], liberties[i]) {
+					vital++
+				}
+			}
+			if vital < 2 {
+				delete(aliveChains, i)
+				changed = true
+			}
+		}
+
+		// Remove regions touching a chain that is no longer alive
+		for j := range aliveRegions {
+			for i := range regions[j].neighbours {
+				if !aliveChains[i] {
+					delete(aliveRegions, j)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	aliveLiberties := make(map[point]bool)
+	for i := range chains {
+		if aliveChains[i] {
+			alive = append(alive, chains[i])
+			for _, liberty := range chains[i].liberties {
+				aliveLiberties[liberty] = true
+			}
+		}
+	}
+
+	// Only regions small enough that every empty cell is a liberty of the living chains
+	// are safe: the opponent cannot make two eyes in them
+	for j := range regions {
+		if !aliveRegions[j] || len(regions[j].neighbours) == 0 {
+			continue
+		}
+		small := true
+		for _, cell := range regions[j].empties {
+			if !aliveLiberties[cell] {
+				small = false
+				break
+			}
+		}
+		if small {
+			territory = append(territory, regions[j].points...)
+		}
+	}
+	return alive, territory
+}
+
+// SuggestLifeStatus marks every dot as alive or dead using Benson's algorithm for both colors.
+// Dots inside an opponent's unconditional territory are dead, everything else is left unknown.
+// Like UnconditionalLife, it is a suggestion: the dead dots still count for their color.
+func (b *Board) SuggestLifeStatus() (alive, dead []point) {
+	for _, color := range []cellState{blue, red} {
+		groups, territory := b.UnconditionalLife(color)
+		for _, group := range groups {
+			alive = append(alive, group.stones...)
+		}
+		for _, cell := range territory {
+			if b.cells[cell.x][cell.y] != empty {
+				dead = append(dead, cell)
+			}
+		}
+	}
+	return alive, dead
+}
+
+// Score counts the cells each player is sure to end the game with: their dots, which are
+// never removed, and the empty clusters enclosed by the player to move alone, which any dot
+// they play fills once filling has started. Every other empty cell is still open: with no
+// captures, the opponent may play inside an eye, so the territory of UnconditionalLife does
+// not count, and liberties shared in seki, see SekiPoints, border both colors and never
+// count. On a full board every dot counts for its color.
+func (b *Board) Score() (blueScore, redScore int) {
+	blueScore, redScore = b.Count(blue), b.Count(red)
+	if b.moves == 0 {
+		return blueScore, redScore // The next dot is the first one, which fills nothing
+	}
+
+	for _, region := range b.EmptyRegions() {
+		if !region.borders[b.next] || region.borders[opponent(b.next)] {
+			continue
+		}
+		if b.next == blue {
+			blueScore += len(region.points)
+		} else {
+			redScore += len(region.points)
+		}
+	}
+	return blueScore, redScore
+}
+
+// DecidedWinner reports the winner once a player holds more than half of the board by Score.
+// Score only counts cells nobody can take away any more, so the game ends there.
+func (b *Board) DecidedWinner() (cellState, bool) {
+	return b.DecidedWinnerWithKomi(0)
+}
+
+// DecidedWinnerWithKomi reports the winner once komi, the dots given to red, can no longer
+// change the outcome either: the leader holds more than half of the board by Score, and
+// enough more that red cannot catch up with komi, or blue with red's komi.
+func (b *Board) DecidedWinnerWithKomi(komi float64) (cellState, bool) {
+	cells := float64(b.size * b.size)
+	blueScore, redScore := b.Score()
+	// At the end blue has at least blueScore and red at most the other cells, and the other way round
+	switch {
+	case 2*float64(blueScore)-cells-komi > 0:
+		return blue, true
+	case cells-2*float64(redScore)-komi < 0:
+		return red, true
+	}
+	return empty, false
+}
+
+// isVitalRegion reports whether all empty cells of the region are liberties of a chain.
+func isVitalRegion(region enclosedRegion, liberties map[point]bool) bool {
+	if len(region.empties) == 0 {
+		return false
+	}
+
+	for _, cell := range region.empties {
+		if !liberties[cell] {
+			return false
+		}
+	}
+	return true
+}
+
+// enclosedRegions splits the cells not holding the given color into connected regions.
+func (b *Board) enclosedRegions(color cellState, chainAt map[point]int) []enclosedRegion {
+	visited := make([][]bool, b.size)
+	for i := range visited {
+		visited[i] = make([]bool, b.size)
+	}
+
+	var regions []enclosedRegion
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if b.cells[x][y] == color || visited[x][y] {
+				continue
+			}
+
+			region := enclosedRegion{neighbours: make(map[int]bool)}
+			stack := []point{{x, y}}
+			visited[x][y] = true
+			for len(stack) > 0 {
+				cell := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				region.points = append(region.points, cell)
+				if b.cells[cell.x][cell.y] == empty {
+					region.empties = append(region.empties, cell)
+				}
+
+				for _, dir := range directions {
+					nx, ny := cell.x+dir.dx, cell.y+dir.dy
+					if !b.onBoard(nx, ny) {
+						continue
+					}
+					if b.cells[nx][ny] == color {
+						region.neighbours[chainAt[point{nx, ny}]] = true
+					} else if !visited[nx][ny] {
+						visited[nx][ny] = true
+						stack = append(stack, point{nx, ny})
+					}
+				}
+			}
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// Additional methods for handling unconditional life functionalities
+// ...
@@ -0,0 +1,99 @@
+package main
+
+import "testing"
+
+// The blue wall has two eyes holding red dots, unconditionally alive by Benson's algorithm,
+// but red can still fill either eye.
+const bensonEyes = `
+O O X . O
+O . X . .
+X X X X .
+O . X . .
+O O X . .
+`
+
+func TestUnconditionalLifeFindsTwoEyes(t *testing.T) {
+	board, err := ParseText(bensonEyes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alive, territory := board.UnconditionalLife(blue)
+	if len(alive) != 1 || len(alive[0].stones) != 8 {
+		t.Errorf("want the blue wall alive, got %d groups", len(alive))
+	}
+	if len(territory) != 8 {
+		t.Errorf("want the two eyes of 4 cells as territory, got %s", formatPoints(territory, board.Size()))
+	}
+	if _, dead := board.SuggestLifeStatus(); len(dead) != 6 {
+		t.Errorf("want the 6 red dots in the eyes dead, got %s", formatPoints(dead, board.Size()))
+	}
+
+	// Filling an eye ends its life
+	board.Play(red, 1, 1)
+	if alive, _ := board.UnconditionalLife(blue); len(alive) != 0 {
+		t.Errorf("the blue wall is still alive with one eye")
+	}
+}
+
+// TestScoreIgnoresFillableEyes checks that territory the opponent can still fill neither
+// counts nor ends the game.
+func TestScoreIgnoresFillableEyes(t *testing.T) {
+	board, err := ParseText(bensonEyes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board.Pass(blue)
+
+	if blueScore, redScore := board.Score(); blueScore != 8 || redScore != 7 {
+		t.Errorf("got the score %d-%d, want the dots 8-7", blueScore, redScore)
+	}
+	if winner, decided := board.DecidedWinner(); decided {
+		t.Errorf("the game is decided for %s", winner)
+	}
+
+	board.Play(red, 1, 1)
+	if blueScore, redScore := board.Score(); blueScore != 8 || redScore != 8 {
+		t.Errorf("after red fills an eye, got the score %d-%d, want 8-8", blueScore, redScore)
+	}
+	if winner, decided := board.DecidedWinner(); decided {
+		t.Errorf("after red fills an eye, the game is decided for %s", winner)
+	}
+}
+
+// TestScoreCountsClustersOfThePlayerToMove checks that an enclosed cluster counts for the
+// player whose next dot fills it, and only once filling has started.
+func TestScoreCountsClustersOfThePlayerToMove(t *testing.T) {
+	tests := []struct {
+		name           string
+		pass           cellState // Who passes before scoring, empty for nobody
+		blue, red      int
+		decidedForBlue bool
+	}{
+		{"before the first dot", empty, 5, 1, false},
+		{"blue to move", red, 9, 1, true},
+		{"red to move", blue, 5, 1, false},
+	}
+	for _, test := range tests {
+		board, err := ParseText(`
+. . X .
+. . X .
+X X X .
+. . . O
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.pass != empty {
+			board.Pass(test.pass)
+		}
+
+		blueScore, redScore := board.Score()
+		if blueScore != test.blue || redScore != test.red {
+			t.Errorf("%s: got the score %d-%d, want %d-%d", test.name, blueScore, redScore, test.blue, test.red)
+		}
+		if winner, decided := board.DecidedWinnerWithKomi(0.5); decided != test.decidedForBlue || decided && winner != blue {
+			t.Errorf("%s: got the winner %s, decided %v", test.name, winner, decided)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type cellState int
+
+const (
+	empty cellState = iota
+	red
+	blue
+)
+
+func (c cellState) String() string {
+	switch c {
+	case blue:
+		return "Blue"
+	case red:
+		return "Red"
+	default:
+		return "Empty"
+	}
+}
+
+// point is a position on the board.
+type point struct{ x, y int }
+
+// maxBoardSize is the largest board played: vertices name each column with one letter,
+// A to Z without I, as Go players and GTP do.
+const maxBoardSize = len(columnLetters)
+
+// formatVertex writes a cell the way Go players read it: a column letter, skipping I, and
+// a row number counted from the bottom.
+func formatVertex(p point, size int) string {
+	return fmt.Sprintf("%c%d", columnLetters[p.x], size-p.y)
+}
+
+// parseVertex reads a cell written by formatVertex, e.g. C4. Letters are case insensitive.
+func parseVertex(vertex string, size int) (point, error) {
+	if len(vertex) < 2 {
+		return point{}, fmt.Errorf("invalid vertex %q", vertex)
+	}
+
+	x := strings.IndexByte(columnLetters, strings.ToUpper(vertex)[0])
+	row, err := strconv.Atoi(vertex[1:])
+	if x < 0 || err != nil {
+		return point{}, fmt.Errorf("invalid vertex %q", vertex)
+	}
+
+	p := point{x, size - row}
+	if p.x >= size || p.y < 0 || p.y >= size {
+		return point{}, fmt.Errorf("vertex %q is outside the board", vertex)
+	}
+	return p, nil
+}
+
+// directions lists the four neighbours of a point.
+var directions = []struct{ dx, dy int }{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// Board holds the dots of a game and applies the filling rules, without any UI.
+type Board struct {
+	size  int
+	cells [][]cellState
+	moves int       // Dots placed by the players, filled ones are not counted
+	next  cellState // Color of the player to move
+}
+
+// Group is a connected set of dots of one color.
+type Group struct {
+	color     cellState
+	stones    []point
+	liberties []point // Empty points adjacent to the group
+}
+
+// Region is a connected set of empty points.
+type Region struct {
+	points  []point
+	borders map[cellState]bool // Colors around the region, empty marks the grid edge
+}
+
+// NewBoard creates an empty board of the given size.
+func NewBoard(size int) *Board {
+	cells := make([][]cellState, size)
+	for i := range cells {
+		cells[i] = make([]cellState, size)
+	}
+
+	return &Board{
+		size:  size,
+		cells: cells,
+		next:  blue,
+	}
+}
+
+// Size returns the number of lines of the board.
+func (b *Board) Size() int {
+	return b.size
+}
+
+// At returns the state of a cell, cells outside the board are empty.
+func (b *Board) At(x, y int) cellState {
+	if !b.onBoard(x, y) {
+		return empty
+	}
+	return b.cells[x][y]
+}
+
+func (b *Board) onBoard(x, y int) bool {
+	return x >= 0 && y >= 0 && x < b.size && y < b.size
+}
+
+// ToMove returns the color of the next dot: blue moves first, then the players alternate.
+func (b *Board) ToMove() cellState {
+	return b.next
+}
+
+// Count returns the number of cells of the given color.
+func (b *Board) Count(color cellState) int {
+	count := 0
+	for x := range b.cells {
+		for y := range b.cells[x] {
+			if b.cells[x][y] == color {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// IsFull reports whether every cell holds a dot, which ends the game.
+func (b *Board) IsFull() bool {
+	return b.Count(empty) == 0
+}
+
+// Play places a dot and fills the clusters it encloses. It returns the filled cells.
+func (b *Board) Play(color cellState, x, y int) []point {
+	b.cells[x][y] = color
+	b.moves++
+	b.next = opponent(color)
+
+	// Check and fill clusters only after the second dot is placed
+	if b.moves > 1 {
+		return b.CheckAndFillClusters()
+	}
+	return nil
+}
+
+// Pass hands the turn to the other player without placing a dot.
+func (b *Board) Pass(color cellState) {
+	b.moves++
+	b.next = opponent(color)
+}
+
+// Setup puts a dot on the board without playing a move, as loaded games do for handicap
+// stones. It neither fills clusters nor changes the turn.
+func (b *Board) Setup(color cellState, x, y int) {
+	b.cells[x][y] = color
+}
+
+// Apply plays a move or a pass and returns the filled cells.
+func (b *Board) Apply(m move) []point {
+	if m.pass {
+		b.Pass(m.color)
+		return nil
+	}
+	return b.Play(m.color, m.x, m.y)
+}
+
+// Clear removes all dots from the board.
+func (b *Board) Clear() {
+	for x := range b.cells {
+		for y := range b.cells[x] {
+			b.cells[x][y] = empty
+		}
+	}
+	b.moves = 0
+	b.next = blue
+}
+
+// Copy returns an independent copy of the board, e.g. for simulating moves.
+func (b *Board) Copy() *Board {
+	c := NewBoard(b.size)
+	for x := range b.cells {
+		copy(c.cells[x], b.cells[x])
+	}
+	c.moves = b.moves
+	c.next = b.next
+	return c
+}
+
+// CheckAndFillClusters fills every empty cluster enclosed by a single color and returns the filled cells.
+func (b *Board) CheckAndFillClusters() []point {
+	var filled []point
+	for _, region := range b.EmptyRegions() {
+		filled = append(filled, b.fillClusterIfEnclosed(region.points, region.borders)...)
+	}
+	return filled
+}
+
+func (b *Board) findCluster(x, y int, visited [][]bool) []point {
+	if !b.onBoard(x, y) || visited[x][y] || b.cells[x][y] != empty {
+		return nil
+	}
+
+	visited[x][y] = true
+	cluster := []point{{x, y}}
+
+	// Recursively search adjacent cells
+	for _, dir := range directions {
+		cluster = append(cluster, b.findCluster(x+dir.dx, y+dir.dy, visited)...)
+	}
+
+	return cluster
+}
+
+func (b *Board) determineClusterBorders(cluster []point) map[cellState]bool {
+	borders := make(map[cellState]bool)
+
+	for _, cell := range cluster {
+		for _, dir := range directions {
+			nx, ny := cell.x+dir.dx, cell.y+dir.dy
+
+			if !b.onBoard(nx, ny) {
+				borders[empty] = true // Mark grid edge as a border
+				continue
+			}
+
+			if b.cells[nx][ny] != empty {
+				borders[b.cells[nx][ny]] = true
+			}
+		}
+	}
+
+	return borders
+}
+
+func (b *Board) fillClusterIfEnclosed(cluster []point, borders map[cellState]bool) []point {
+	// Check if cluster is enclosed by either one color or a combination of one color and grid edges
+	if len(borders) == 1 || (len(borders) == 2 && borders[empty]) {
+		var fillWith cellState
+		colorFound := false
+
+		for colorNow := range borders {
+			if colorNow != empty {
+				if colorFound {
+					return nil // More than one non-empty color found, do not fill
+				}
+				fillWith = colorNow
+				colorFound = true
+			}
+		}
+
+		if colorFound {
+			for _, cell := range cluster {
+				b.cells[cell.x][cell.y] = fillWith
+			}
+			return cluster
+		}
+	}
+	return nil
+}
+
+// GroupAt returns the group of dots containing the given cell. It reports false for empty cells.
+func (b *Board) GroupAt(x, y int) (Group, bool) {
+	if b.At(x, y) == empty {
+		return Group{}, false
+	}
+
+	visited := make([][]bool, b.size)
+	for i := range visited {
+		visited[i] = make([]bool, b.size)
+	}
+	return b.findGroup(x, y, visited), true
+}
+
+// Liberties returns the number of liberties of the group at the given cell, 0 for empty cells.
+func (b *Board) Liberties(x, y int) int {
+	group, ok := b.GroupAt(x, y)
+	if !ok {
+		return 0
+	}
+	return len(group.liberties)
+}
+
+// Groups returns all groups of the given color.
+func (b *Board) Groups(color cellState) []Group {
+	if color == empty {
+		return nil
+	}
+
+	visited := make([][]bool, b.size)
+	for i := range visited {
+		visited[i] = make([]bool, b.size)
+	}
+
+	var groups []Group
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if b.cells[x][y] == color && !visited[x][y] {
+				groups = append(groups, b.findGroup(x, y, visited))
+			}
+		}
+	}
+	return groups
+}
+
+// EmptyRegions returns all clusters of empty cells with the colors bordering them.
+func (b *Board) EmptyRegions() []Region {
+	visited := make([][]bool, b.size)
+	for i := range visited {
+		visited[i] = make([]bool, b.size)
+	}
+
+	var regions []Region
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if b.cells[x][y] == empty && !visited[x][y] {
+				cluster := b.findCluster(x, y, visited)
+				regions = append(regions, Region{points: cluster, borders: b.determineClusterBorders(cluster)})
+			}
+		}
+	}
+	return regions
+}
+
+// findGroup collects the group at the given cell, marking its dots as visited.
+func (b *Board) findGroup(x, y int, visited [][]bool) Group {
+	group := Group{color: b.cells[x][y]}
+	seenLiberty := make(map[point]bool)
+
+	stack := []point{{x, y}}
+	visited[x][y] = true
+	for len(stack) > 0 {
+		cell := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		group.stones = append(group.stones, cell)
+
+		for _, dir := range directions {
+			nx, ny := cell.x+dir.dx, cell.y+dir.dy
+			if !b.onBoard(nx, ny) {
+				continue
+			}
+
+			switch b.cells[nx][ny] {
+			case empty:
+				if !seenLiberty[point{nx, ny}] {
+					seenLiberty[point{nx, ny}] = true
+					group.liberties = append(group.liberties, point{nx, ny})
+				}
+			case group.color:
+				if !visited[nx][ny] {
+					visited[nx][ny] = true
+					stack = append(stack, point{nx, ny})
+				}
+			}
+		}
+	}
+
+	return group
+}
+
+// Additional methods for handling board functionalities
+// ...
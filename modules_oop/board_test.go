@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestBoardGolden sets up the position of each testdata/board file, plays the moves listed
+// after it as "play X C3" lines, and describes the board after each move: the cells filled,
+// the groups with their liberties and the empty regions with the colors around them.
+func TestBoardGolden(t *testing.T) {
+	names, inputs := goldenInputs(t, "board", ".txt")
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			var position []string
+			var moves [][]string
+			for _, line := range strings.Split(inputs[name], "\n") {
+				if fields := strings.Fields(line); len(fields) > 0 && fields[0] == "play" {
+					moves = append(moves, fields[1:])
+				} else {
+					position = append(position, line)
+				}
+			}
+
+			board, err := ParseText(strings.Join(position, "\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got strings.Builder
+			describeBoard(&got, board)
+			for _, fields := range moves {
+				if len(fields) != 2 {
+					t.Fatalf("play %v: want a color and a vertex", fields)
+				}
+				color := textSymbols[[]rune(fields[0])[0]]
+				p, err := parseVertex(fields[1], board.Size())
+				if err != nil || color == empty || board.At(p.x, p.y) != empty {
+					t.Fatalf("play %v: not a legal move", fields)
+				}
+
+				filled := board.Play(color, p.x, p.y)
+				fmt.Fprintf(&got, "\nplay %s %s, filled %s\n", fields[0], fields[1], formatPoints(filled, board.Size()))
+				describeBoard(&got, board)
+			}
+			checkGolden(t, name, got.String())
+		})
+	}
+}
+
+// describeBoard writes the position, its groups and its empty regions.
+func describeBoard(w *strings.Builder, b *Board) {
+	w.WriteString(b.Text(TextOptions{coordinates: true}))
+
+	var groups []string
+	for _, color := range []cellState{blue, red} {
+		for _, group := range b.Groups(color) {
+			first := group.stones[0]
+			if found, ok := b.GroupAt(first.x, first.y); !ok || len(found.stones) != len(group.stones) {
+				groups = append(groups, fmt.Sprintf("GroupAt %s disagrees with Groups", formatVertex(first, b.size)))
+			}
+			groups = append(groups, fmt.Sprintf("%s group %s: %d liberties %s", color, formatPoints(group.stones, b.size),
+				b.Liberties(first.x, first.y), formatPoints(group.liberties, b.size)))
+		}
+	}
+	sort.Strings(groups)
+
+	var regions []string
+	for _, region := range b.EmptyRegions() {
+		var borders []string
+		for _, color := range []cellState{blue, red} {
+			if region.borders[color] {
+				borders = append(borders, color.String())
+			}
+		}
+		if region.borders[empty] {
+			borders = append(borders, "edge")
+		}
+		regions = append(regions, fmt.Sprintf("region %s: borders %s", formatPoints(region.points, b.size), strings.Join(borders, ", ")))
+	}
+	sort.Strings(regions)
+
+	for _, line := range append(groups, regions...) {
+		w.WriteString(line + "\n")
+	}
+}
+
+// formatPoints writes cells as sorted vertices, e.g. [A1 B2].
+func formatPoints(points []point, size int) string {
+	vertices := make([]string, len(points))
+	for i, p := range points {
+		vertices[i] = formatVertex(p, size)
+	}
+	sort.Strings(vertices)
+	return "[" + strings.Join(vertices, " ") + "]"
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Bot chooses the moves of a computer player. It gets a copy of the board, which it may change.
+type Bot interface {
+	GenMove(board *Board, color cellState) move
+}
+
+// Players offered for each color in the new game dialog: a human, the random bot
+// or a bot at a difficulty level.
+const (
+	humanPlayer        = "Human"
+	randomBotPlayer    = "Random bot"
+	beginnerPlayer     = "Beginner"
+	intermediatePlayer = "Intermediate"
+	strongPlayer       = "Strong"
+)
+
+var playerNames = []string{humanPlayer, randomBotPlayer, beginnerPlayer, intermediatePlayer, strongPlayer}
+
+// Searches of the MCTS bot at the difficulty levels using it.
+var (
+	intermediateMCTSConfig = MCTSConfig{playouts: 500}
+	strongMCTSConfig       = MCTSConfig{timeLimit: 3 * time.Second, rave: true}
+)
+
+// The hint button searches like a strong player and suggests its best few moves.
+var hintMCTSConfig = strongMCTSConfig
+
+const hintCount = 3
+
+// NewBot creates the bot of the given player name, nil for a human. Beginners face the greedy
+// bot, intermediate players a short tree search and strong players a long one, which plays
+// perfectly on boards up to 5x5 once the solver can finish.
+func NewBot(name string) Bot {
+	switch name {
+	case randomBotPlayer:
+		return NewRandomBot()
+	case beginnerPlayer:
+		return NewGreedyBot()
+	case intermediatePlayer:
+		return NewMCTSBot(intermediateMCTSConfig)
+	case strongPlayer:
+		return NewSolverBot()
+	default:
+		return nil
+	}
+}
+
+// NewBotByFlag creates a bot named on the command line: random, greedy, mcts or solver,
+// the MCTS bot searching as configured.
+func NewBotByFlag(name string, mcts MCTSConfig) (Bot, error) {
+	switch name {
+	case "random":
+		return NewRandomBot(), nil
+	case "greedy":
+		return NewGreedyBot(), nil
+	case "mcts":
+		return NewMCTSBot(mcts), nil
+	case "solver":
+		return NewSolverBot(), nil
+	default:
+		return nil, fmt.Errorf("unknown bot %q, use random, greedy, mcts or solver", name)
+	}
+}
+
+// RandomBot plays uniformly random legal moves, which is any empty cell, and passes on a full board.
+// It is the baseline other bots are measured against.
+type RandomBot struct {
+	random *rand.Rand
+}
+
+func NewRandomBot() *RandomBot {
+	return &RandomBot{random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (b *RandomBot) GenMove(board *Board, color cellState) move {
+	cells := board.LegalMoves()
+	if len(cells) == 0 {
+		return move{color: color, pass: true}
+	}
+	cell := cells[b.random.Intn(len(cells))]
+	return move{color: color, x: cell.x, y: cell.y}
+}
+
+// GreedyBot plays the move gaining the most cells right away: those it fills plus those the
+// opponent would fill by playing there. Among equal moves it avoids self-atari that loses the
+// ladder, then prefers cells next to its own dots, then picks at random.
+type GreedyBot struct {
+	random *rand.Rand
+}
+
+func NewGreedyBot() *GreedyBot {
+	return &GreedyBot{random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// greedyScore ranks a move, comparing the gain first and the tie-breaks after it.
+type greedyScore struct {
+	gain      int  // Cells filled by the move or denied to the opponent; dots are never captured
+	safe      bool // Not in atari after the move, or escaping the ladder that follows
+	adjacency int  // Own dots next to the move
+}
+
+func (s greedyScore) beats(other greedyScore) bool {
+	if s.gain != other.gain {
+		return s.gain > other.gain
+	}
+	if s.safe != other.safe {
+		return s.safe
+	}
+	// Adjacency only matters when something is at stake: clumping dots together
+	// all game long loses even to random play
+	return s.gain > 0 && s.adjacency > other.adjacency
+}
+
+func (b *GreedyBot) GenMove(board *Board, color cellState) move {
+	var best []point
+	var bestScore greedyScore
+	for _, cell := range board.LegalMoves() {
+		score := greedyEvaluate(board, color, cell)
+		switch {
+		case len(best) == 0 || score.beats(bestScore):
+			best, bestScore = []point{cell}, score
+		case !bestScore.beats(score):
+			best = append(best, cell) // A tie
+		}
+	}
+
+	if len(best) == 0 {
+		return move{color: color, pass: true}
+	}
+	cell := best[b.random.Intn(len(best))]
+	return move{color: color, x: cell.x, y: cell.y}
+}
+
+// greedyEvaluate simulates the move on a copy of the board.
+func greedyEvaluate(board *Board, color cellState, cell point) greedyScore {
+	var score greedyScore
+	for _, dir := range directions {
+		nx, ny := cell.x+dir.dx, cell.y+dir.dy
+		if board.onBoard(nx, ny) && board.At(nx, ny) == color {
+			score.adjacency++
+		}
+	}
+
+	next := board.Copy()
+	score.gain = len(next.Play(color, cell.x, cell.y))
+	score.safe = next.Liberties(cell.x, cell.y) != 1
+	if !score.safe {
+		caught, _ := next.ReadLadder(cell.x, cell.y)
+		score.safe = !caught
+	}
+
+	denied := board.Copy()
+	score.gain += len(denied.Play(opponent(color), cell.x, cell.y))
+	return score
+}
+
+// LegalMoves lists the cells a dot may be placed on: all the empty ones.
+func (b *Board) LegalMoves() []point {
+	var cells []point
+	for x := range b.cells {
+		for y := range b.cells[x] {
+			if b.cells[x][y] == empty {
+				cells = append(cells, point{x, y})
+			}
+		}
+	}
+	return cells
+}
+
+// Additional methods for handling bot functionalities
+// ...
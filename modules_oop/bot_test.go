@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRandomBotPlaysEveryFreeCell(t *testing.T) {
+	bot := &RandomBot{random: rand.New(rand.NewSource(1))}
+	board, err := ParseText(`
+X . O
+. X .
+O . .
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	played := make(map[point]int)
+	for i := 0; i < 500; i++ {
+		m := bot.GenMove(board, red)
+		if m.color != red || m.pass {
+			t.Fatalf("asked for a red move, got %+v", m)
+		}
+		if board.At(m.x, m.y) != empty {
+			t.Fatalf("played on the taken cell %s", formatVertex(point{m.x, m.y}, 3))
+		}
+		played[point{m.x, m.y}]++
+	}
+	// Each of the 5 free cells comes up about 100 times
+	for _, cell := range board.LegalMoves() {
+		if count := played[cell]; count < 60 || count > 140 {
+			t.Errorf("%s was played %d times out of 500", formatVertex(cell, 3), count)
+		}
+	}
+}
+
+func TestRandomBotPassesOnFullBoard(t *testing.T) {
+	board, err := ParseText("X O\nO X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := NewRandomBot().GenMove(board, blue); !m.pass || m.color != blue {
+		t.Errorf("got %+v on a full board, want a blue pass", m)
+	}
+}
+
+// TestGreedyBotTakesTheLargestGain checks that the greedy bot seals the top of the board,
+// whether its own wall would fill it or the opponent's.
+func TestGreedyBotTakesTheLargestGain(t *testing.T) {
+	for _, test := range []struct {
+		name, position string
+	}{
+		{"fill", `
+. . . . .
+X X X X .
+. . . . .
+. . . . .
+. . . . O
+`},
+		{"deny", `
+. . . . .
+O O O O .
+. . . . .
+. . . . .
+. . . . X
+`},
+	} {
+		board, err := ParseText(test.position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		board.Pass(blue) // Filling starts with the next dot
+		board.Pass(red)
+
+		// E3 encloses the 6 cells above it, E4 only the top row
+		bot := &GreedyBot{random: rand.New(rand.NewSource(1))}
+		if m := bot.GenMove(board, blue); m.pass || formatVertex(point{m.x, m.y}, 5) != "E3" {
+			t.Errorf("%s: got %+v, want E3", test.name, m)
+		}
+	}
+}
+
+// TestGreedyBotAvoidsLostLadders checks that a dot left in atari counts as unsafe only when
+// the ladder that follows catches it.
+func TestGreedyBotAvoidsLostLadders(t *testing.T) {
+	board, err := ParseText(strings.Replace(ladderStart, "O X", "O .", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2 := point{2, 5}
+	if score := greedyEvaluate(board, blue, c2); score.safe || score.gain != 0 {
+		t.Errorf("C2 scores %+v, want unsafe with no gain", score)
+	}
+	bot := &GreedyBot{random: rand.New(rand.NewSource(1))}
+	for i := 0; i < 100; i++ {
+		if m := bot.GenMove(board, blue); m.x == c2.x && m.y == c2.y {
+			t.Fatal("the greedy bot played into the ladder at C2")
+		}
+	}
+
+	board.Setup(blue, 5, 2) // A ladder breaker at F5
+	if score := greedyEvaluate(board, blue, c2); !score.safe {
+		t.Errorf("C2 scores %+v with the ladder broken, want safe", score)
+	}
+}
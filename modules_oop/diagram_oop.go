@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Layout of exported diagrams, in pixels.
+const (
+	diagramCellSize = 32
+	diagramMargin   = 24 // Room for the coordinates around the board
+)
+
+var (
+	diagramBackground = color.NRGBA{R: 220, G: 179, B: 92, A: 255}
+	diagramBlue       = color.NRGBA{B: 255, A: 255}
+	diagramRed        = color.NRGBA{R: 255, A: 255}
+)
+
+// DiagramOptions selects what is drawn on exported board diagrams.
+type DiagramOptions struct {
+	coordinates bool
+	moveNumbers bool
+	lastMove    bool
+}
+
+// diagram is a position ready to be drawn, with the moves that led to it.
+type diagram struct {
+	board    *Board
+	numbers  map[point]int // Move number of each dot still showing the move played there
+	lastMove *point
+}
+
+// newDiagram replays the game up to the given node. Filled and setup dots carry no number.
+func newDiagram(tree *GameTree, node *GameNode, size int) diagram {
+	d := diagram{
+		board:   tree.BoardAt(node, size),
+		numbers: make(map[point]int),
+	}
+
+	for _, step := range node.path()[1:] {
+		if step.move.pass {
+			continue
+		}
+		cell := point{step.move.x, step.move.y}
+		d.numbers[cell] = step.depth()
+		d.lastMove = &cell
+	}
+	return d
+}
+
+// ExportDiagram writes the current position of the game as PNG or SVG, chosen by the file extension.
+func ExportDiagram(path string, tree *GameTree, size int, opts DiagramOptions) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	d := newDiagram(tree, tree.current, size)
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		err = d.WriteSVG(file, opts)
+	} else {
+		err = d.WritePNG(file, opts)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// center returns the pixel position of a cell's intersection.
+func (d diagram) center(x, y int) (int, int) {
+	return diagramMargin + x*diagramCellSize + diagramCellSize/2, diagramMargin + y*diagramCellSize + diagramCellSize/2
+}
+
+func (d diagram) imageSize() int {
+	return 2*diagramMargin + d.board.size*diagramCellSize
+}
+
+// coordinateLabels returns the column letters and row numbers, as formatVertex writes them.
+func (d diagram) coordinateLabels(i int) (column, row string) {
+	vertex := formatVertex(point{i, i}, d.board.size)
+	return vertex[:1], fmt.Sprintf("%d", d.board.size-i)
+}
+
+// WritePNG draws the diagram as a PNG image.
+func (d diagram) WritePNG(w io.Writer, opts DiagramOptions) error {
+	return png.Encode(w, d.Image(opts))
+}
+
+// Image draws the diagram.
+func (d diagram) Image(opts DiagramOptions) image.Image {
+	side := d.imageSize()
+	img := image.NewNRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(img, img.Bounds(), image.NewUniform(diagramBackground), image.Point{}, draw.Src)
+
+	// Grid lines
+	first, _ := d.center(0, 0)
+	last, _ := d.center(d.board.size-1, 0)
+	for i := 0; i < d.board.size; i++ {
+		line, _ := d.center(i, 0)
+		draw.Draw(img, image.Rect(line, first, line+1, last+1), image.Black, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(first, line, last+1, line+1), image.Black, image.Point{}, draw.Src)
+	}
+
+	if opts.coordinates {
+		for i := 0; i < d.board.size; i++ {
+			column, row := d.coordinateLabels(i)
+			cx, cy := d.center(i, i)
+			drawCenteredText(img, column, cx, diagramMargin/2, color.Black)
+			drawCenteredText(img, column, cx, side-diagramMargin/2, color.Black)
+			drawCenteredText(img, row, diagramMargin/2, cy, color.Black)
+			drawCenteredText(img, row, side-diagramMargin/2, cy, color.Black)
+		}
+	}
+
+	radius := diagramCellSize * 2 / 5
+	for x := 0; x < d.board.size; x++ {
+		for y := 0; y < d.board.size; y++ {
+			stone := d.board.At(x, y)
+			if stone == empty {
+				continue
+			}
+
+			cx, cy := d.center(x, y)
+			stoneColor := diagramBlue
+			if stone == red {
+				stoneColor = diagramRed
+			}
+			drawCircle(img, cx, cy, radius, 0, stoneColor)
+
+			if number, ok := d.numbers[point{x, y}]; ok && opts.moveNumbers {
+				drawCenteredText(img, fmt.Sprintf("%d", number), cx, cy, color.White)
+			}
+		}
+	}
+
+	if opts.lastMove && d.lastMove != nil {
+		cx, cy := d.center(d.lastMove.x, d.lastMove.y)
+		if opts.moveNumbers {
+			drawCircle(img, cx, cy, radius-2, radius-4, color.White) // A ring around the number
+		} else {
+			drawCircle(img, cx, cy, radius/3, 0, color.White)
+		}
+	}
+
+	return img
+}
+
+// WriteSVG draws the diagram as an SVG document.
+func (d diagram) WriteSVG(w io.Writer, opts DiagramOptions) error {
+	out := bufio.NewWriter(w)
+	side := d.imageSize()
+
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", side, side, side, side)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="%s"/>`+"\n", side, side, svgColor(diagramBackground))
+
+	first, _ := d.center(0, 0)
+	last, _ := d.center(d.board.size-1, 0)
+	for i := 0; i < d.board.size; i++ {
+		line, _ := d.center(i, 0)
+		fmt.Fprintf(out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", line, first, line, last)
+		fmt.Fprintf(out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", first, line, last, line)
+	}
+
+	if opts.coordinates {
+		for i := 0; i < d.board.size; i++ {
+			column, row := d.coordinateLabels(i)
+			cx, cy := d.center(i, i)
+			writeSVGText(out, column, cx, diagramMargin/2, "black")
+			writeSVGText(out, column, cx, side-diagramMargin/2, "black")
+			writeSVGText(out, row, diagramMargin/2, cy, "black")
+			writeSVGText(out, row, side-diagramMargin/2, cy, "black")
+		}
+	}
+
+	radius := diagramCellSize * 2 / 5
+	for x := 0; x < d.board.size; x++ {
+		for y := 0; y < d.board.size; y++ {
+			stone := d.board.At(x, y)
+			if stone == empty {
+				continue
+			}
+
+			cx, cy := d.center(x, y)
+			stoneColor := diagramBlue
+			if stone == red {
+				stoneColor = diagramRed
+			}
+			fmt.Fprintf(out, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", cx, cy, radius, svgColor(stoneColor))
+
+			if number, ok := d.numbers[point{x, y}]; ok && opts.moveNumbers {
+				writeSVGText(out, fmt.Sprintf("%d", number), cx, cy, "white")
+			}
+		}
+	}
+
+	if opts.lastMove && d.lastMove != nil {
+		cx, cy := d.center(d.lastMove.x, d.lastMove.y)
+		if opts.moveNumbers {
+			fmt.Fprintf(out, `<circle cx="%d" cy="%d" r="%d" fill="none" stroke="white" stroke-width="2"/>`+"\n", cx, cy, radius-3)
+		} else {
+			fmt.Fprintf(out, `<circle cx="%d" cy="%d" r="%d" fill="white"/>`+"\n", cx, cy, radius/3)
+		}
+	}
+
+	out.WriteString("</svg>\n")
+	return out.Flush()
+}
+
+// drawCircle fills the ring between the inner and outer radius, a disc when inner is 0.
+func drawCircle(img draw.Image, cx, cy, outer, inner int, c color.Color) {
+	for y := cy - outer; y <= cy+outer; y++ {
+		for x := cx - outer; x <= cx+outer; x++ {
+			distance := (x-cx)*(x-cx) + (y-cy)*(y-cy)
+			if distance <= outer*outer && distance >= inner*inner {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
+
+// drawCenteredText writes text centered on the given pixel with the built-in bitmap font.
+func drawCenteredText(img draw.Image, text string, cx, cy int, c color.Color) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+	}
+	width := drawer.MeasureString(text)
+	// The 7x13 face has an ascent of 11 pixels, half of which lies above the center
+	drawer.Dot = fixed.P(cx, cy+5).Sub(fixed.Point26_6{X: width / 2})
+	drawer.DrawString(text)
+}
+
+func writeSVGText(out *bufio.Writer, text string, x, y int, fill string) {
+	fmt.Fprintf(out, `<text x="%d" y="%d" fill="%s" font-family="sans-serif" font-size="12" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n", x, y, fill, text)
+}
+
+func svgColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Additional methods for handling diagram functionalities
+// ...
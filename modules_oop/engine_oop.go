@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Time limits for external engines. A move may take long, any other command should be instant.
+// They are variables so that tests can shorten them.
+var (
+	engineStartTimeout   = 10 * time.Second
+	engineCommandTimeout = 5 * time.Second
+	engineMoveTimeout    = 60 * time.Second
+)
+
+// errEngineTimeout is returned when an engine does not answer in time.
+var errEngineTimeout = errors.New("the engine did not answer in time")
+
+// EngineConfig is the external engine the user last played against.
+type EngineConfig struct {
+	Path      string `json:"path"`
+	Arguments string `json:"arguments"` // Split on spaces, e.g. "--mode gtp"
+	Color     string `json:"color"`     // Color played by the engine, "Blue" or "Red"
+}
+
+// engineConfigPath returns the engine settings file next to the autosave file.
+func engineConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-in-go", "engine.json"), nil
+}
+
+// readEngineConfig loads the engine settings, defaulting to an engine playing red.
+func readEngineConfig(path string) EngineConfig {
+	config := EngineConfig{Color: red.String()}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &config) // Broken settings are simply entered again
+	}
+	return config
+}
+
+// writeConfigFile saves settings as JSON, creating the config directory if needed.
+func writeConfigFile(path string, config any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// gtpResponse is the text of one answer from an engine, or why none came.
+type gtpResponse struct {
+	text string
+	err  error
+}
+
+// GTPClient talks to an engine process over the Go Text Protocol.
+type GTPClient struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan gtpResponse
+}
+
+// StartGTPClient launches an engine and checks that it answers GTP commands.
+func StartGTPClient(path string, args ...string) (*GTPClient, error) {
+	cmd := exec.Command(path, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start the engine: %w", err)
+	}
+
+	c := &GTPClient{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan gtpResponse, 1),
+	}
+	go c.readResponses(stdout)
+
+	if _, err := c.Send("protocol_version", engineStartTimeout); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("%s does not speak GTP: %w", filepath.Base(path), err)
+	}
+	return c, nil
+}
+
+// Send writes a command and waits for its answer. An error answer from the engine
+// is returned as an error with the engine's message.
+// After a timeout the answers are out of step with the commands, so the client should be closed.
+func (c *GTPClient) Send(command string, timeout time.Duration) (string, error) {
+	if _, err := io.WriteString(c.stdin, command+"\n"); err != nil {
+		return "", fmt.Errorf("the engine has stopped: %w", err)
+	}
+
+	select {
+	case response, ok := <-c.responses:
+		if !ok {
+			return "", errors.New("the engine has stopped")
+		}
+		return response.text, response.err
+	case <-time.After(timeout):
+		return "", errEngineTimeout
+	}
+}
+
+// Close asks the engine to quit and kills it if it does not.
+func (c *GTPClient) Close() error {
+	c.Send("quit", engineCommandTimeout)
+	c.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- c.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(engineCommandTimeout):
+		c.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// readResponses collects the lines of each answer up to the empty line ending it.
+func (c *GTPClient) readResponses(stdout io.Reader) {
+	defer close(c.responses)
+
+	scanner := bufio.NewScanner(stdout)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line != "" {
+			lines = append(lines, line)
+			continue
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		c.responses <- parseGTPResponse(lines)
+		lines = nil
+	}
+}
+
+// parseGTPResponse strips the status character and the optional id from an answer.
+func parseGTPResponse(lines []string) gtpResponse {
+	first := lines[0]
+	success := strings.HasPrefix(first, "=")
+	if !success && !strings.HasPrefix(first, "?") {
+		return gtpResponse{err: fmt.Errorf("unexpected engine output %q", first)}
+	}
+
+	first = strings.TrimLeft(first[1:], "0123456789")
+	text := strings.TrimSpace(strings.Join(append([]string{first}, lines[1:]...), "\n"))
+	if !success {
+		return gtpResponse{err: fmt.Errorf("the engine refused: %s", text)}
+	}
+	return gtpResponse{text: text}
+}
+
+// EnginePlayer lets an external GTP engine play one color. The engine keeps its own board,
+// which is brought up to date with play commands before each of its moves. GTP has no way to
+// set up a position, so the engine has to play by the filling rules itself, as another
+// go-in-go gtp does: engines playing the usual rules of Go, e.g. GNU Go, capture dots and
+// fill no cells, so their board soon differs from the game's.
+type EnginePlayer struct {
+	client *GTPClient
+	config EngineConfig // Started from, e.g. to start the engine again for a resumed game
+	color  cellState
+	size   int
+	sent   []string // Play commands given since the last clear_board
+}
+
+// NewEnginePlayer starts the engine of the config for a game on a board of the given size.
+func NewEnginePlayer(config EngineConfig, size int, komi float64) (*EnginePlayer, error) {
+	client, err := StartGTPClient(config.Path, strings.Fields(config.Arguments)...)
+	if err != nil {
+		return nil, err
+	}
+
+	color := red
+	if config.Color == blue.String() {
+		color = blue
+	}
+	p := &EnginePlayer{client: client, config: config, color: color}
+	if err := p.newGame(size); err == nil {
+		_, err = client.Send(fmt.Sprintf("komi %g", komi), engineCommandTimeout)
+	}
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// GenMove asks the engine for its move on the board reached by the given play commands,
+// see enginePlays. It reports true when the engine resigns. A move on an occupied cell is
+// an error, the engine's board no longer being that of the game.
+func (p *EnginePlayer) GenMove(board *Board, plays []string) (move, bool, error) {
+	size := board.Size()
+	if err := p.sync(size, plays); err != nil {
+		return move{}, false, err
+	}
+
+	reply, err := p.client.Send("genmove "+gtpColor(p.color), engineMoveTimeout)
+	if err != nil {
+		return move{}, false, err
+	}
+
+	m := move{color: p.color}
+	switch strings.ToLower(reply) {
+	case "resign":
+		return m, true, nil
+	case "pass":
+		m.pass = true
+		p.sent = append(p.sent, enginePlay(m, size))
+		return m, false, nil
+	}
+
+	cell, err := parseVertex(reply, size)
+	if err != nil {
+		return move{}, false, fmt.Errorf("the engine played %q: %w", reply, err)
+	}
+	if board.At(cell.x, cell.y) != empty {
+		return move{}, false, fmt.Errorf("the engine played on the occupied cell %s", formatVertex(cell, size))
+	}
+	m.x, m.y = cell.x, cell.y
+	p.sent = append(p.sent, enginePlay(m, size))
+	return m, false, nil
+}
+
+// Close stops the engine process.
+func (p *EnginePlayer) Close() error {
+	return p.client.Close()
+}
+
+func (p *EnginePlayer) newGame(size int) error {
+	if _, err := p.client.Send(fmt.Sprintf("boardsize %d", size), engineCommandTimeout); err != nil {
+		return err
+	}
+	if _, err := p.client.Send("clear_board", engineCommandTimeout); err != nil {
+		return err
+	}
+	p.size = size
+	p.sent = nil
+	return nil
+}
+
+// sync sends the plays the engine has not seen yet. When the game went another way,
+// e.g. after a reset or a move in an earlier position, the engine starts over.
+func (p *EnginePlayer) sync(size int, plays []string) error {
+	known := size == p.size && len(p.sent) <= len(plays)
+	for i := 0; known && i < len(p.sent); i++ {
+		known = p.sent[i] == plays[i]
+	}
+	if !known {
+		if err := p.newGame(size); err != nil {
+			return err
+		}
+	}
+
+	for _, play := range plays[len(p.sent):] {
+		if _, err := p.client.Send(play, engineCommandTimeout); err != nil {
+			return err
+		}
+		p.sent = append(p.sent, play)
+	}
+	return nil
+}
+
+// enginePlays lists the play commands recreating the position of a game tree node. The engine
+// fills the enclosed cells itself, see EnginePlayer, so only the moves are sent. Setup dots,
+// e.g. of a pasted position, are sent as moves of their color.
+func enginePlays(node *GameNode, size int) []string {
+	var plays []string
+	for _, step := range node.path() {
+		if step.parent != nil {
+			plays = append(plays, enginePlay(step.move, size))
+		}
+		for _, stone := range step.setup {
+			plays = append(plays, enginePlay(stone, size))
+		}
+	}
+	return plays
+}
+
+// enginePlay writes the play command of a move.
+func enginePlay(m move, size int) string {
+	if m.pass {
+		return fmt.Sprintf("play %s pass", gtpColor(m.color))
+	}
+	return fmt.Sprintf("play %s %s", gtpColor(m.color), formatVertex(point{m.x, m.y}, size))
+}
+
+// Additional methods for handling engine functionalities
+// ...
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeEngineVariable makes the test binary behave as a GTP engine, see TestMain.
+const fakeEngineVariable = "GO_IN_GO_FAKE_ENGINE"
+
+// TestMain runs the test binary as a fake engine when fakeEngineVariable names a behavior,
+// so that the tests can start it as an external engine process.
+func TestMain(m *testing.M) {
+	if behavior := os.Getenv(fakeEngineVariable); behavior != "" {
+		runFakeEngine(behavior)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeEngine answers GTP commands on stdin like go-in-go gtp with a seeded random bot,
+// except for what the behavior changes:
+//   - exit: quits at once without answering
+//   - mute: never answers
+//   - slow: never answers genmove
+//   - resign: resigns every genmove
+//   - occupied: answers genmove with the vertex of the last play
+func runFakeEngine(behavior string) {
+	if behavior == "exit" {
+		os.Exit(1)
+	}
+
+	engine := NewGTPEngine(&RandomBot{random: rand.New(rand.NewSource(1))})
+	lastPlay := "pass"
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		_, command, args := parseGTPLine(scanner.Text())
+		if behavior == "mute" || (behavior == "slow" && command == "genmove") {
+			continue
+		}
+
+		var result string
+		var err error
+		switch {
+		case command == "genmove" && behavior == "resign":
+			result = "resign"
+		case command == "genmove" && behavior == "occupied":
+			result = lastPlay
+		default:
+			result, err = engine.Execute(command, args)
+		}
+		if command == "play" && err == nil {
+			lastPlay = args[1]
+		}
+
+		if err != nil && err != errGTPQuit {
+			fmt.Printf("? %s\n\n", err)
+		} else {
+			fmt.Printf("= %s\n\n", result)
+		}
+		if err == errGTPQuit {
+			return
+		}
+	}
+}
+
+// startFakeEngine starts the test binary as an engine with the given behavior playing red
+// on a 5x5 board. The time limits are shortened for the test.
+func startFakeEngine(t *testing.T, behavior string) (*EnginePlayer, error) {
+	t.Setenv(fakeEngineVariable, behavior)
+	for _, timeout := range []*time.Duration{&engineStartTimeout, &engineCommandTimeout, &engineMoveTimeout} {
+		saved := *timeout
+		*timeout = time.Second
+		t.Cleanup(func() { *timeout = saved })
+	}
+
+	config := EngineConfig{Path: os.Args[0], Color: red.String()}
+	engine, err := NewEnginePlayer(config, 5, 0)
+	if err == nil {
+		t.Cleanup(func() { engine.Close() })
+	}
+	return engine, err
+}
+
+// engineGame plays the given moves, alternating colors from blue, and returns the
+// board and the play commands the engine gets.
+func engineGame(t *testing.T, vertices ...string) (*Board, []string) {
+	t.Helper()
+	tree := NewGameTree()
+	board := NewBoard(5)
+	color := blue
+	for _, vertex := range vertices {
+		p, err := parseVertex(vertex, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := move{color: color, x: p.x, y: p.y}
+		tree.Play(m)
+		board.Apply(m)
+		color = opponent(color)
+	}
+	return board, enginePlays(tree.current, 5)
+}
+
+func TestEngineStartFailure(t *testing.T) {
+	if _, err := NewEnginePlayer(EngineConfig{Path: "/nonexistent/engine"}, 5, 0); err == nil {
+		t.Error("an engine that does not exist started")
+	}
+	for _, behavior := range []string{"exit", "mute"} {
+		if _, err := startFakeEngine(t, behavior); err == nil {
+			t.Errorf("%s: an engine that does not answer started", behavior)
+		}
+	}
+}
+
+func TestEnginePlays(t *testing.T) {
+	engine, err := startFakeEngine(t, "normal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// B2 encloses A1, which the engine fills itself as the game does
+	vertices := []string{"A2", "E5", "B1", "E4"}
+	for i := 0; i < 3; i++ {
+		board, plays := engineGame(t, vertices...)
+		m, resigned, err := engine.GenMove(board, plays)
+		if err != nil || resigned || m.color != red {
+			t.Fatalf("move %d: got %+v, resigned %v, %v", len(vertices), m, resigned, err)
+		}
+		if m.pass || board.At(m.x, m.y) != empty {
+			t.Fatalf("move %d: the engine played %+v on a taken cell or passed", len(vertices), m)
+		}
+		vertices = append(vertices, formatVertex(point{m.x, m.y}, 5))
+
+		// Blue answers on the first free cell after the engine's move
+		board, _ = engineGame(t, vertices...)
+		free := 0
+		for board.At(free%5, free/5) != empty {
+			free++
+		}
+		vertices = append(vertices, formatVertex(point{free % 5, free / 5}, 5))
+	}
+
+	// Going back to an earlier position starts the engine over
+	board, plays := engineGame(t, "C3")
+	if _, _, err := engine.GenMove(board, plays); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEngineTimeout(t *testing.T) {
+	engine, err := startFakeEngine(t, "slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board, plays := engineGame(t, "C3")
+	if _, _, err := engine.GenMove(board, plays); !errors.Is(err, errEngineTimeout) {
+		t.Errorf("got %v, want a timeout", err)
+	}
+}
+
+func TestEngineResign(t *testing.T) {
+	engine, err := startFakeEngine(t, "resign")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board, plays := engineGame(t, "C3")
+	if _, resigned, err := engine.GenMove(board, plays); err != nil || !resigned {
+		t.Errorf("got resigned %v, %v, want a resignation", resigned, err)
+	}
+}
+
+func TestEngineOccupiedCell(t *testing.T) {
+	engine, err := startFakeEngine(t, "occupied")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board, plays := engineGame(t, "C3")
+	_, _, err = engine.GenMove(board, plays)
+	if err == nil || !strings.Contains(err.Error(), "occupied cell C3") {
+		t.Errorf("got %v, want an error for the occupied cell", err)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import "time"
+
+// move is a single dot placed by a player, or a pass.
+type move struct {
+	color cellState
+	x, y  int
+	pass  bool
+}
+
+// GameNode is one move of the game tree together with its continuations.
+type GameNode struct {
+	id       int
+	move     move
+	parent   *GameNode
+	children []*GameNode // The first child continues the main line
+	setup    []move      // Dots added after the move, e.g. handicap stones on the root
+	comment  string
+	playedAt time.Time     // When the move was played here, zero for loaded games
+	clock    time.Duration // Game clock when the move was played
+}
+
+// GameTree keeps every variation explored in a game and the node currently shown.
+type GameTree struct {
+	root    *GameNode
+	current *GameNode
+	nodes   map[int]*GameNode // Lookup by id, used by the tree view
+	nextID  int
+}
+
+// NewGameTree creates a game tree holding only the empty starting position.
+func NewGameTree() *GameTree {
+	root := &GameNode{}
+	return &GameTree{
+		root:    root,
+		current: root,
+		nodes:   map[int]*GameNode{root.id: root},
+		nextID:  1,
+	}
+}
+
+// Play makes m the current move. An existing continuation with the same move is
+// followed, otherwise a new variation is branched off the current node.
+func (t *GameTree) Play(m move) *GameNode {
+	for _, child := range t.current.children {
+		if child.move == m {
+			t.current = child
+			return child
+		}
+	}
+
+	node := &GameNode{id: t.nextID, move: m, parent: t.current}
+	t.nextID++
+	t.nodes[node.id] = node
+	t.current.children = append(t.current.children, node)
+	t.current = node
+	return node
+}
+
+// Back steps to the previous move. It reports false at the start of the game.
+func (t *GameTree) Back() bool {
+	if t.current.parent == nil {
+		return false
+	}
+	t.current = t.current.parent
+	return true
+}
+
+// Forward steps to the next move of the current variation.
+func (t *GameTree) Forward() bool {
+	if len(t.current.children) == 0 {
+		return false
+	}
+	t.current = t.current.children[0]
+	return true
+}
+
+// NextVariation switches to the next alternative of the current move.
+func (t *GameTree) NextVariation() bool {
+	return t.switchVariation(1)
+}
+
+// PreviousVariation switches to the previous alternative of the current move.
+func (t *GameTree) PreviousVariation() bool {
+	return t.switchVariation(-1)
+}
+
+func (t *GameTree) switchVariation(step int) bool {
+	parent := t.current.parent
+	if parent == nil {
+		return false
+	}
+
+	for i, sibling := range parent.children {
+		if sibling == t.current {
+			next := i + step
+			if next < 0 || next >= len(parent.children) {
+				return false
+			}
+			t.current = parent.children[next]
+			return true
+		}
+	}
+	return false
+}
+
+// GoToMainLine jumps to the main line position with the same move number,
+// or to the end of the main line if it is shorter.
+func (t *GameTree) GoToMainLine() {
+	depth := t.current.depth()
+	node := t.root
+	for i := 0; i < depth && len(node.children) > 0; i++ {
+		node = node.children[0]
+	}
+	t.current = node
+}
+
+// GoTo makes node the current position.
+func (t *GameTree) GoTo(node *GameNode) {
+	t.current = node
+}
+
+// Variation returns the nodes of the variation shown: the path to the current node
+// followed by its main continuation, root included.
+func (t *GameTree) Variation() []*GameNode {
+	return append(t.Path(), t.current.line()[1:]...)
+}
+
+// Moves returns the moves leading from the start of the game to the current node.
+func (t *GameTree) Moves() []move {
+	var moves []move
+	for _, node := range t.Path()[1:] {
+		moves = append(moves, node.move)
+	}
+	return moves
+}
+
+// Path returns the nodes from the start of the game to the current node, root included.
+func (t *GameTree) Path() []*GameNode {
+	return t.current.path()
+}
+
+// BoardAt replays the game up to the given node on a new board.
+func (t *GameTree) BoardAt(node *GameNode, size int) *Board {
+	board := NewBoard(size)
+	for _, step := range node.path() {
+		if step.parent != nil {
+			board.Apply(step.move)
+		}
+		for _, stone := range step.setup {
+			board.Setup(stone.color, stone.x, stone.y)
+		}
+	}
+	return board
+}
+
+// mainLineEnd returns the last node of the first variation.
+func (t *GameTree) mainLineEnd() *GameNode {
+	nodes := t.root.line()
+	return nodes[len(nodes)-1]
+}
+
+// MainLine returns the moves of the first variation from the start to its end.
+func (t *GameTree) MainLine() []move {
+	if len(t.root.children) == 0 {
+		return nil
+	}
+
+	var moves []move
+	for _, node := range t.root.children[0].line() {
+		moves = append(moves, node.move)
+	}
+	return moves
+}
+
+// depth returns the move number of the node, the root being 0.
+func (n *GameNode) depth() int {
+	depth := 0
+	for node := n; node.parent != nil; node = node.parent {
+		depth++
+	}
+	return depth
+}
+
+// path returns the nodes from the root to this node.
+func (n *GameNode) path() []*GameNode {
+	var nodes []*GameNode
+	for node := n; node != nil; node = node.parent {
+		nodes = append(nodes, node)
+	}
+	// Reverse to get them in playing order
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	return nodes
+}
+
+// variationPath returns the index of each node among its siblings, from the root to this node.
+func (n *GameNode) variationPath() []int {
+	indexes := []int{}
+	for _, node := range n.path()[1:] {
+		for i, sibling := range node.parent.children {
+			if sibling == node {
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	return indexes
+}
+
+// goToVariationPath follows the indexes written by variationPath as far as the tree allows.
+func (t *GameTree) goToVariationPath(indexes []int) {
+	node := t.root
+	for _, i := range indexes {
+		if i < 0 || i >= len(node.children) {
+			break
+		}
+		node = node.children[i]
+	}
+	t.current = node
+}
+
+// isVariation reports whether the node is an alternative to its parent's main continuation.
+func (n *GameNode) isVariation() bool {
+	return n.parent != nil && n.parent.children[0] != n
+}
+
+// line returns the node followed by its main continuation.
+func (n *GameNode) line() []*GameNode {
+	var nodes []*GameNode
+	for node := n; node != nil; {
+		nodes = append(nodes, node)
+		if len(node.children) == 0 {
+			break
+		}
+		node = node.children[0]
+	}
+	return nodes
+}
+
+// Additional methods for handling game tree functionalities
+// ...
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// variationTree plays B2 C3 B1 on the main line, then A1 as a variation of C3.
+func variationTree() *GameTree {
+	tree := NewGameTree()
+	tree.Play(move{color: blue, x: 1, y: 1})
+	tree.Play(move{color: red, x: 2, y: 0})
+	tree.Play(move{color: blue, x: 1, y: 2})
+	tree.Back()
+	tree.Back()
+	tree.Play(move{color: red, x: 0, y: 2})
+	return tree
+}
+
+func TestGameTreePlayBranches(t *testing.T) {
+	tree := variationTree()
+	first := tree.root.children[0]
+	if len(first.children) != 2 || !tree.current.isVariation() || first.children[0].isVariation() {
+		t.Fatalf("want the main line and one variation after the first move, got %d continuations", len(first.children))
+	}
+	if got := tree.current.depth(); got != 2 {
+		t.Errorf("the variation is at move %d, want 2", got)
+	}
+
+	// Playing a move already in the tree follows it instead of adding a variation
+	tree.Back()
+	node := tree.Play(move{color: red, x: 2, y: 0})
+	if node != first.children[0] || len(first.children) != 2 || len(tree.nodes) != 5 {
+		t.Errorf("replaying C3 added a node, %d continuations and %d nodes", len(first.children), len(tree.nodes))
+	}
+
+	want := []move{{color: blue, x: 1, y: 1}, {color: red, x: 2, y: 0}, {color: blue, x: 1, y: 2}}
+	if got := tree.MainLine(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got the main line %v, want %v", got, want)
+	}
+	if got := tree.Variation(); len(got) != 4 || got[3] != tree.mainLineEnd() {
+		t.Errorf("the variation shown has %d nodes, want the main line", len(got))
+	}
+}
+
+func TestGameTreeNavigation(t *testing.T) {
+	tree := variationTree()
+	variation := tree.current
+
+	if !tree.PreviousVariation() || tree.current != tree.root.children[0].children[0] {
+		t.Fatal("PreviousVariation did not switch to the main line")
+	}
+	if tree.PreviousVariation() {
+		t.Error("PreviousVariation went past the first continuation")
+	}
+	if !tree.NextVariation() || tree.current != variation || tree.NextVariation() {
+		t.Error("NextVariation did not switch back to the only variation")
+	}
+	if tree.Forward() {
+		t.Error("Forward went past the end of the variation")
+	}
+
+	// The main line position with the same move number
+	tree.GoToMainLine()
+	if tree.current != tree.root.children[0].children[0] {
+		t.Errorf("GoToMainLine went to move %d", tree.current.depth())
+	}
+
+	for tree.Back() {
+	}
+	if tree.current != tree.root {
+		t.Error("Back stopped before the start")
+	}
+	for tree.Forward() {
+	}
+	if tree.current != tree.mainLineEnd() {
+		t.Error("Forward stopped before the end of the main line")
+	}
+}
+
+func TestGameTreeVariationPath(t *testing.T) {
+	tree := variationTree()
+	path := tree.current.variationPath()
+	if want := []int{0, 1}; !reflect.DeepEqual(path, want) {
+		t.Fatalf("got the variation path %v, want %v", path, want)
+	}
+
+	other := variationTree()
+	other.GoTo(other.root)
+	other.goToVariationPath(path)
+	if got := other.current.move; got != tree.current.move {
+		t.Errorf("the variation path leads to %+v, want %+v", got, tree.current.move)
+	}
+
+	// Indexes the tree does not have stop at the last node found
+	other.goToVariationPath([]int{0, 5, 0})
+	if other.current != other.root.children[0] {
+		t.Errorf("an invalid path led to move %d", other.current.depth())
+	}
+}
+
+func TestGameTreeBoardAt(t *testing.T) {
+	tree := variationTree()
+	tree.current.setup = []move{{color: blue, x: 2, y: 2}}
+
+	board := tree.BoardAt(tree.current, 3)
+	for _, cell := range []struct {
+		x, y  int
+		color cellState
+	}{{1, 1, blue}, {0, 2, red}, {2, 2, blue}, {2, 0, empty}} {
+		if got := board.At(cell.x, cell.y); got != cell.color {
+			t.Errorf("%s holds %s, want %s", formatVertex(point{cell.x, cell.y}, 3), got, cell.color)
+		}
+	}
+	if board.ToMove() != blue {
+		t.Errorf("%s to move after two moves", board.ToMove())
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// update rewrites the golden files with the current output: go test -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares got with the golden file testdata/<name>.golden.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run go test -update to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s\n--- got:\n%s--- want:\n%s", path, got, want)
+	}
+}
+
+// goldenInputs returns the names, without the extension, and the contents of the files
+// of a testdata directory with the given extension, sorted by name.
+func goldenInputs(t *testing.T, dir, extension string) ([]string, map[string]string) {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", dir, "*"+extension))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no %s files in testdata/%s", extension, dir)
+	}
+
+	var names []string
+	inputs := make(map[string]string)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := dir + "/" + strings.TrimSuffix(filepath.Base(path), extension)
+		names = append(names, name)
+		inputs[name] = string(data)
+	}
+	sort.Strings(names)
+	return names, inputs
+}
+
+// sameCells reports whether two boards hold the same dots.
+func sameCells(a, b *Board) bool {
+	if a.Size() != b.Size() {
+		return false
+	}
+	for x := 0; x < a.Size(); x++ {
+		for y := 0; y < a.Size(); y++ {
+			if a.At(x, y) != b.At(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
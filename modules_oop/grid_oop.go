@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
@@ -8,29 +9,17 @@ import (
 	"image/color"
 )
 
-type cellState int
-
-const (
-	empty cellState = iota
-	red
-	blue
-)
-
 type tappableArea struct {
 	widget.BaseWidget
 	onTap func(x, y int)
 	x, y  int
 }
 
-func newTappableArea(x, y int, onTap func(x, y int), cellStates [][]cellState) *tappableArea {
+func newTappableArea(x, y int, onTap func(x, y int)) *tappableArea {
 	t := &tappableArea{
-		onTap: func(x, y int) {
-			if cellStates[x][y] == empty {
-				onTap(x, y)
-			}
-		},
-		x: x,
-		y: y,
+		onTap: onTap,
+		x:     x,
+		y:     y,
 	}
 	t.ExtendBaseWidget(t)
 	return t
@@ -50,37 +39,40 @@ func (t *tappableArea) Tapped(_ *fyne.PointEvent) {
 // Grid manages the game grid.
 type Grid struct {
 	container     *fyne.Container
-	cellStates    [][]cellState
+	board         *Board
 	dotsContainer *fyne.Container
+	influence     *fyne.Container // Territory estimate shading, drawn under the dots
+	showInfluence bool
+	life          *fyne.Container // Alive and dead markers, drawn over the dots
+	showLife      bool
+	ladder        *fyne.Container // Numbered moves of the ladders read out on demand
+	hints         *fyne.Container // Moves suggested by the hint button
+	gameOver      bool
 	cellSize      int
 	gridOffsetX   float32
 	gridOffsetY   float32
 	gridSize      int
-	blueDotCount  *int
-	redDotCount   *int
-	dotCount      *int
 	onDotPlaced   func() // Callback function
+	onMovePlayed  func(m move)
+	onTurnEnded   func()      // Called after each move played on the grid, e.g. to let an engine answer
+	canPlay       func() bool // Whether taps may play, false while the computer thinks or has the move
 	timer         *Timer
 	gameWindow    *GameWindow
 }
 
 func NewGrid(gridSize, cellSize int, gridOffsetX, gridOffsetY float32, onDotPlaced func(), timer *Timer, gameWindow *GameWindow) *Grid {
-	cellStates := make([][]cellState, gridSize)
-	for i := range cellStates {
-		cellStates[i] = make([]cellState, gridSize)
-	}
-
 	return &Grid{
 		container:     container.NewWithoutLayout(),
-		cellStates:    cellStates,
+		board:         NewBoard(gridSize),
 		dotsContainer: container.NewWithoutLayout(),
+		influence:     container.NewWithoutLayout(),
+		life:          container.NewWithoutLayout(),
+		ladder:        container.NewWithoutLayout(),
+		hints:         container.NewWithoutLayout(),
 		cellSize:      cellSize,
 		gridOffsetX:   gridOffsetX,
 		gridOffsetY:   gridOffsetY,
 		gridSize:      gridSize,
-		blueDotCount:  new(int),
-		redDotCount:   new(int),
-		dotCount:      new(int),
 		onDotPlaced:   onDotPlaced,
 		timer:         timer,
 		gameWindow:    gameWindow,
@@ -110,23 +102,14 @@ func (g *Grid) DrawGrid() {
 	for y := 0; y < g.gridSize; y++ {
 		for x := 0; x < g.gridSize; x++ {
 			area := newTappableArea(x, y, func(x, y int) {
-				if g.cellStates[x][y] != empty {
-					// Skip if the cell is already filled
+				// A computer player may be playing meanwhile, PlayMove skips filled cells
+				g.gameWindow.mu.Lock()
+				defer g.gameWindow.mu.Unlock()
+				if g.canPlay != nil && !g.canPlay() {
 					return
 				}
-
-				var currentColor cellState
-				if *g.dotCount%2 == 0 {
-					currentColor = blue
-				} else {
-					currentColor = red
-				}
-
-				// Place a dot of the determined color
-				g.PlaceDot(currentColor, x, y)
-				// Refresh the grid container to show the new dot
-				g.container.Refresh()
-			}, g.cellStates) // Pass g.cellStates as the fourth argument
+				g.PlayMove(move{color: g.board.ToMove(), x: x, y: y})
+			})
 
 			area.Resize(fyne.NewSize(float32(g.cellSize), float32(g.cellSize)))
 			area.Move(fyne.NewPos(float32(x)*float32(g.cellSize)+g.gridOffsetX, float32(y)*float32(g.cellSize)+g.gridOffsetY))
@@ -135,5 +118,196 @@ func (g *Grid) DrawGrid() {
 	}
 }
 
+// PlayMove plays and records a move as if its cell had been tapped.
+func (g *Grid) PlayMove(m move) {
+	if g.gameOver || (!m.pass && g.board.At(m.x, m.y) != empty) {
+		// Skip if the game is decided or the cell is already filled
+		return
+	}
+
+	if g.onMovePlayed != nil {
+		g.onMovePlayed(m)
+	}
+
+	// Place a dot of the determined color
+	g.ApplyMove(m)
+	// Refresh the grid container to show the new dot
+	g.container.Refresh()
+
+	if g.onTurnEnded != nil {
+		g.onTurnEnded()
+	}
+}
+
+// Clear removes all dots without redrawing the grid lines.
+func (g *Grid) Clear() {
+	g.board.Clear()
+	g.dotsContainer.RemoveAll()
+	g.dotsContainer.Refresh()
+	g.gameOver = false
+	g.HideLadders()
+	g.HideHints()
+	g.DrawInfluence()
+	g.DrawLife()
+}
+
+// SetInfluenceVisible toggles the territory estimate overlay.
+func (g *Grid) SetInfluenceVisible(show bool) {
+	g.showInfluence = show
+	g.DrawInfluence()
+}
+
+// DrawInfluence shades each empty cell by the player likely to own it.
+func (g *Grid) DrawInfluence() {
+	g.influence.RemoveAll()
+	if g.showInfluence {
+		values := g.board.Influence()
+		owners := g.board.EstimateTerritory()
+		for x := 0; x < g.gridSize; x++ {
+			for y := 0; y < g.gridSize; y++ {
+				if owners[x][y] == empty {
+					continue // Dots, neutral cells and seki are not shaded
+				}
+
+				// Stronger influence gives a more opaque shade
+				alpha := uint8(min(40+abs(values[x][y])*8, 160))
+				shade := color.NRGBA{B: 255, A: alpha}
+				if owners[x][y] == red {
+					shade = color.NRGBA{R: 255, A: alpha}
+				}
+
+				square := canvas.NewRectangle(shade)
+				square.Resize(fyne.NewSize(float32(g.cellSize)*0.8, float32(g.cellSize)*0.8))
+				square.Move(fyne.NewPos((float32(x)+0.1)*float32(g.cellSize)+g.gridOffsetX, (float32(y)+0.1)*float32(g.cellSize)+g.gridOffsetY))
+				g.influence.Add(square)
+			}
+		}
+	}
+	g.influence.Refresh()
+}
+
+// SetLifeVisible toggles the alive and dead markers suggested by Benson's algorithm.
+func (g *Grid) SetLifeVisible(show bool) {
+	g.showLife = show
+	g.DrawLife()
+}
+
+// DrawLife rings the dots that are unconditionally alive and crosses the dead ones.
+func (g *Grid) DrawLife() {
+	g.life.RemoveAll()
+	if g.showLife {
+		alive, dead := g.board.SuggestLifeStatus()
+		markerSize := float32(g.cellSize) / 5 * 2
+
+		for _, cell := range alive {
+			ring := canvas.NewCircle(color.Transparent)
+			ring.StrokeColor = color.White
+			ring.StrokeWidth = 2
+			ring.Resize(fyne.NewSize(markerSize*0.6, markerSize*0.6))
+			ring.Move(fyne.NewPos((float32(cell.x)+0.5)*float32(g.cellSize)+g.gridOffsetX-markerSize*0.3, (float32(cell.y)+0.5)*float32(g.cellSize)+g.gridOffsetY-markerSize*0.3))
+			g.life.Add(ring)
+		}
+		for _, cell := range dead {
+			cross := canvas.NewText("×", color.White)
+			cross.TextStyle = fyne.TextStyle{Bold: true}
+			cross.TextSize = markerSize
+			cross.Alignment = fyne.TextAlignCenter
+			cross.Resize(fyne.NewSize(markerSize, markerSize))
+			cross.Move(fyne.NewPos((float32(cell.x)+0.5)*float32(g.cellSize)+g.gridOffsetX-markerSize/2, (float32(cell.y)+0.5)*float32(g.cellSize)+g.gridOffsetY-markerSize/2))
+			g.life.Add(cross)
+		}
+	}
+	g.life.Refresh()
+}
+
+// ShowLadders reads the ladder of every group in atari and numbers its moves on the grid.
+// It returns one line per group telling how the ladder ends.
+func (g *Grid) ShowLadders() []string {
+	g.ladder.RemoveAll()
+	markerSize := float32(g.cellSize) / 5 * 2
+
+	var results []string
+	for _, group := range g.board.GroupsInAtari() {
+		stone := group.stones[0]
+		works, sequence := g.board.ReadLadder(stone.x, stone.y)
+
+		for i, m := range sequence {
+			markerColor := color.NRGBA{B: 255, A: 140}
+			if m.color == red {
+				markerColor = color.NRGBA{R: 255, A: 140}
+			}
+			marker := canvas.NewCircle(markerColor)
+			marker.Resize(fyne.NewSize(markerSize, markerSize))
+			marker.Move(fyne.NewPos((float32(m.x)+0.5)*float32(g.cellSize)+g.gridOffsetX-markerSize/2, (float32(m.y)+0.5)*float32(g.cellSize)+g.gridOffsetY-markerSize/2))
+
+			number := canvas.NewText(fmt.Sprintf("%d", i+1), color.White)
+			number.TextSize = markerSize / 2
+			number.Alignment = fyne.TextAlignCenter
+			number.Resize(fyne.NewSize(markerSize, markerSize))
+			number.Move(marker.Position())
+
+			g.ladder.Add(marker)
+			g.ladder.Add(number)
+		}
+
+		outcome := "escapes"
+		if works {
+			outcome = "is captured"
+		}
+		results = append(results, fmt.Sprintf("%s group at %s %s after %d moves", group.color, formatVertex(stone, g.gridSize), outcome, len(sequence)))
+	}
+
+	g.ladder.Refresh()
+	return results
+}
+
+// HideLadders removes the ladder moves shown on the grid.
+func (g *Grid) HideLadders() {
+	g.ladder.RemoveAll()
+	g.ladder.Refresh()
+}
+
+// ShowHints rings the suggested moves, numbered from the best one, with the share of
+// playouts the player to move won after each. The best move gets a thicker ring.
+func (g *Grid) ShowHints(candidates []MCTSCandidate) {
+	g.hints.RemoveAll()
+	markerSize := float32(g.cellSize) / 5 * 3
+
+	for i, candidate := range candidates {
+		ring := canvas.NewCircle(color.Transparent)
+		ring.StrokeColor = color.NRGBA{G: 160, A: 255}
+		ring.StrokeWidth = 2
+		if i == 0 {
+			ring.StrokeWidth = 4
+		}
+		ring.Resize(fyne.NewSize(markerSize, markerSize))
+		ring.Move(fyne.NewPos((float32(candidate.cell.x)+0.5)*float32(g.cellSize)+g.gridOffsetX-markerSize/2, (float32(candidate.cell.y)+0.5)*float32(g.cellSize)+g.gridOffsetY-markerSize/2))
+
+		label := canvas.NewText(fmt.Sprintf("%d: %.0f%%", i+1, candidate.winRate*100), color.NRGBA{G: 120, A: 255})
+		label.TextStyle = fyne.TextStyle{Bold: i == 0}
+		label.TextSize = markerSize / 3
+		label.Alignment = fyne.TextAlignCenter
+		label.Resize(fyne.NewSize(float32(g.cellSize), markerSize/3))
+		label.Move(fyne.NewPos(float32(candidate.cell.x)*float32(g.cellSize)+g.gridOffsetX, (float32(candidate.cell.y)+0.5)*float32(g.cellSize)+g.gridOffsetY+markerSize/2))
+
+		g.hints.Add(ring)
+		g.hints.Add(label)
+	}
+	g.hints.Refresh()
+}
+
+// HideHints removes the suggested moves from the grid.
+func (g *Grid) HideHints() {
+	g.hints.RemoveAll()
+	g.hints.Refresh()
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
 // Additional methods for handling grid functionalities
 // ...
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// gtpCommands lists the supported Go Text Protocol commands, in the order list_commands prints them.
+var gtpCommands = []string{
+	"protocol_version", "name", "version", "known_command", "list_commands", "quit",
+	"boardsize", "clear_board", "komi", "play", "genmove", "undo", "showboard", "final_score",
+}
+
+// errGTPQuit ends the command loop after the quit command has been answered.
+var errGTPQuit = errors.New("gtp: quit")
+
+// GTPEngine answers Go Text Protocol (version 2) commands with the rules engine, so that
+// GTP tools like GoGui, Sabaki or twogtp can play through it. Blue plays black.
+type GTPEngine struct {
+	size  int
+	komi  float64
+	tree  *GameTree
+	board *Board
+	bot   Bot // Chooses the moves of genmove
+}
+
+// NewGTPEngine creates an engine with an empty 9x9 board, the default of the app,
+// playing the moves of the given bot.
+func NewGTPEngine(bot Bot) *GTPEngine {
+	e := &GTPEngine{
+		size: 9,
+		bot:  bot,
+	}
+	e.clearBoard()
+	return e
+}
+
+// RunGTPCommand runs the gtp command line: go-in-go gtp [-bot name] [-playouts n] [-time d] [-rave].
+func RunGTPCommand(args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("gtp", flag.ContinueOnError)
+	botName := flags.String("bot", "random", "bot choosing the moves: random, greedy, mcts or solver")
+	mcts := mctsFlags(flags)
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return nil // The usage has been printed
+	} else if err != nil {
+		return err
+	}
+
+	bot, err := NewBotByFlag(*botName, *mcts)
+	if err != nil {
+		return err
+	}
+	return NewGTPEngine(bot).Run(in, out)
+}
+
+// mctsFlags registers the search settings of the mcts bot on a command line.
+func mctsFlags(flags *flag.FlagSet) *MCTSConfig {
+	config := &MCTSConfig{}
+	flags.IntVar(&config.playouts, "playouts", 0, "playouts per move of the mcts bot, 0 for no limit")
+	flags.DurationVar(&config.timeLimit, "time", 0, "thinking time per move of the mcts bot, e.g. 2s, 0 for no limit")
+	flags.IntVar(&config.workers, "workers", 0, "goroutines searching in parallel, 0 for one per CPU")
+	flags.BoolVar(&config.rave, "rave", false, "use RAVE in the mcts bot")
+	return config
+}
+
+// Run reads commands line by line and writes a response for each until quit or end of input.
+func (e *GTPEngine) Run(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	writer := bufio.NewWriter(out)
+
+	for scanner.Scan() {
+		id, command, args := parseGTPLine(scanner.Text())
+		if command == "" {
+			continue
+		}
+
+		result, err := e.Execute(command, args)
+		if err != nil && err != errGTPQuit {
+			fmt.Fprintf(writer, "?%s %s\n\n", id, err)
+		} else {
+			fmt.Fprintf(writer, "=%s %s\n\n", id, result)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if err == errGTPQuit {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// Execute runs a single command and returns its response text.
+func (e *GTPEngine) Execute(command string, args []string) (string, error) {
+	switch command {
+	case "protocol_version":
+		return "2", nil
+	case "name":
+		return "Go in Go", nil
+	case "version":
+		return "1.0", nil
+	case "known_command":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		for _, known := range gtpCommands {
+			if known == args[0] {
+				return "true", nil
+			}
+		}
+		return "false", nil
+	case "list_commands":
+		return strings.Join(gtpCommands, "\n"), nil
+	case "quit":
+		return "", errGTPQuit
+	case "boardsize":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		size, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", errors.New("syntax error")
+		}
+		if size < 1 || size > maxBoardSize {
+			return "", errors.New("unacceptable size")
+		}
+		e.size = size
+		e.clearBoard()
+		return "", nil
+	case "clear_board":
+		e.clearBoard()
+		return "", nil
+	case "komi":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		komi, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return "", errors.New("syntax error")
+		}
+		e.komi = komi
+		return "", nil
+	case "play":
+		if len(args) != 2 {
+			return "", errors.New("syntax error")
+		}
+		m, err := e.parseMove(args[0], args[1])
+		if err != nil {
+			return "", err
+		}
+		e.play(m)
+		return "", nil
+	case "genmove":
+		if len(args) != 1 {
+			return "", errors.New("syntax error")
+		}
+		color, err := parseGTPColor(args[0])
+		if err != nil {
+			return "", err
+		}
+		m := e.genMove(color)
+		e.play(m)
+		if m.pass {
+			return "pass", nil
+		}
+		return formatVertex(point{m.x, m.y}, e.size), nil
+	case "undo":
+		if !e.tree.Back() {
+			return "", errors.New("cannot undo")
+		}
+		e.board = e.tree.BoardAt(e.tree.current, e.size)
+		return "", nil
+	case "showboard":
+		return "\n" + strings.TrimRight(e.board.Text(TextOptions{coordinates: true}), "\n"), nil
+	case "final_score":
+		// The result of a finished game, an estimate while it goes on
+		if result, over := gameResult(e.board, e.komi); over {
+			return result, nil
+		}
+		blueScore, redScore := e.board.ScoreEstimate()
+		return marginResult(float64(blueScore-redScore) - e.komi), nil
+	default:
+		return "", errors.New("unknown command")
+	}
+}
+
+func (e *GTPEngine) clearBoard() {
+	e.tree = NewGameTree()
+	e.board = NewBoard(e.size)
+}
+
+func (e *GTPEngine) play(m move) {
+	e.tree.Play(m)
+	e.board.Apply(m)
+}
+
+// parseMove reads the color and vertex of a play command and checks that the cell is free.
+func (e *GTPEngine) parseMove(colorArg, vertexArg string) (move, error) {
+	color, err := parseGTPColor(colorArg)
+	if err != nil {
+		return move{}, err
+	}
+	if strings.EqualFold(vertexArg, "pass") {
+		return move{color: color, pass: true}, nil
+	}
+
+	p, err := parseVertex(vertexArg, e.size)
+	if err != nil {
+		return move{}, err
+	}
+	if e.board.At(p.x, p.y) != empty {
+		return move{}, errors.New("illegal move")
+	}
+	return move{color: color, x: p.x, y: p.y}, nil
+}
+
+// genMove asks the bot for a move, or passes once the game is over.
+func (e *GTPEngine) genMove(color cellState) move {
+	if _, decided := e.board.DecidedWinnerWithKomi(e.komi); decided {
+		return move{color: color, pass: true}
+	}
+	return e.bot.GenMove(e.board.Copy(), color)
+}
+
+// parseGTPLine removes comments and control characters and splits off the optional command id.
+func parseGTPLine(line string) (id, command string, args []string) {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if r < 32 || r == 127 {
+			return -1
+		}
+		return r
+	}, line)
+
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		if _, err := strconv.Atoi(fields[0]); err == nil {
+			id, fields = fields[0], fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return id, "", nil
+	}
+	return id, strings.ToLower(fields[0]), fields[1:]
+}
+
+// parseGTPColor maps GTP colors to players: black is blue, who moves first, white is red.
+func parseGTPColor(arg string) (cellState, error) {
+	switch strings.ToLower(arg) {
+	case "b", "black":
+		return blue, nil
+	case "w", "white":
+		return red, nil
+	default:
+		return empty, errors.New("syntax error")
+	}
+}
+
+// gtpColor is the GTP name of a player's color.
+func gtpColor(color cellState) string {
+	if color == blue {
+		return "B"
+	}
+	return "W"
+}
+
+// Additional methods for handling GTP functionalities
+// ...
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestGTPGolden runs each testdata/gtp command script through the engine. Moves are
+// generated by a random bot with a fixed seed, so that the answers are always the same.
+func TestGTPGolden(t *testing.T) {
+	names, inputs := goldenInputs(t, "gtp", ".gtp")
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			engine := NewGTPEngine(&RandomBot{random: rand.New(rand.NewSource(1))})
+			var got strings.Builder
+			if err := engine.Run(strings.NewReader(inputs[name]), &got); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, name, got.String())
+		})
+	}
+}
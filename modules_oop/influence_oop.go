@@ -0,0 +1,130 @@
+package main
+
+// Parameters of Bouzy's 5/21 algorithm as used by most Go programs.
+const (
+	influenceStoneValue = 128
+	influenceDilations  = 5
+	influenceErosions   = 21
+)
+
+// Influence estimates who is likely to own each cell with Bouzy's dilation and erosion.
+// Positive values belong to blue, negative values to red and zero is neutral.
+func (b *Board) Influence() [][]int {
+	values := make([][]int, b.size)
+	for x := range values {
+		values[x] = make([]int, b.size)
+		for y := range values[x] {
+			switch b.cells[x][y] {
+			case blue:
+				values[x][y] = influenceStoneValue
+			case red:
+				values[x][y] = -influenceStoneValue
+			}
+		}
+	}
+
+	for i := 0; i < influenceDilations; i++ {
+		values = b.dilate(values)
+	}
+	for i := 0; i < influenceErosions; i++ {
+		values = b.erode(values)
+	}
+
+	return values
+}
+
+// EstimateTerritory returns the likely owner of every empty cell, or empty when undecided.
+// Liberties shared in seki are never counted as territory.
+func (b *Board) EstimateTerritory() [][]cellState {
+	values := b.Influence()
+	owners := make([][]cellState, b.size)
+	for x := range owners {
+		owners[x] = make([]cellState, b.size)
+		for y := range owners[x] {
+			if b.cells[x][y] != empty {
+				continue
+			}
+			if values[x][y] > 0 {
+				owners[x][y] = blue
+			} else if values[x][y] < 0 {
+				owners[x][y] = red
+			}
+		}
+	}
+
+	for _, cell := range b.SekiPoints() {
+		owners[cell.x][cell.y] = empty
+	}
+	return owners
+}
+
+// dilate spreads influence to cells that are not touched by the opponent.
+func (b *Board) dilate(values [][]int) [][]int {
+	next := make([][]int, b.size)
+	for x := range values {
+		next[x] = make([]int, b.size)
+		for y := range values[x] {
+			value := values[x][y]
+			positive, negative := b.countNeighbourSigns(values, x, y)
+
+			if value >= 0 && negative == 0 {
+				value += positive
+			}
+			if value <= 0 && positive == 0 {
+				value -= negative
+			}
+			next[x][y] = value
+		}
+	}
+	return next
+}
+
+// erode shrinks influence where it borders neutral or opposing cells.
+func (b *Board) erode(values [][]int) [][]int {
+	next := make([][]int, b.size)
+	for x := range values {
+		next[x] = make([]int, b.size)
+		for y := range values[x] {
+			value := values[x][y]
+			notPositive, notNegative := 0, 0
+			for _, dir := range directions {
+				nx, ny := x+dir.dx, y+dir.dy
+				if !b.onBoard(nx, ny) {
+					continue
+				}
+				if values[nx][ny] <= 0 {
+					notPositive++
+				}
+				if values[nx][ny] >= 0 {
+					notNegative++
+				}
+			}
+
+			if value > 0 {
+				value = max(value-notPositive, 0)
+			} else if value < 0 {
+				value = min(value+notNegative, 0)
+			}
+			next[x][y] = value
+		}
+	}
+	return next
+}
+
+func (b *Board) countNeighbourSigns(values [][]int, x, y int) (positive, negative int) {
+	for _, dir := range directions {
+		nx, ny := x+dir.dx, y+dir.dy
+		if !b.onBoard(nx, ny) {
+			continue
+		}
+		if values[nx][ny] > 0 {
+			positive++
+		} else if values[nx][ny] < 0 {
+			negative++
+		}
+	}
+	return positive, negative
+}
+
+// Additional methods for handling influence functionalities
+// ...
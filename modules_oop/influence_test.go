@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestInfluenceEmptyBoard(t *testing.T) {
+	values := NewBoard(5).Influence()
+	for x := range values {
+		for y := range values[x] {
+			if values[x][y] != 0 {
+				t.Fatalf("%s has influence %d on the empty board", formatVertex(point{x, y}, 5), values[x][y])
+			}
+		}
+	}
+}
+
+// TestInfluenceSymmetry checks that a lone dot radiates evenly, and that a red dot does
+// exactly the opposite of a blue one.
+func TestInfluenceSymmetry(t *testing.T) {
+	board := NewBoard(5)
+	board.Setup(blue, 2, 2)
+	values := board.Influence()
+	board.Setup(red, 2, 2)
+	opposite := board.Influence()
+
+	for x := range values {
+		for y := range values[x] {
+			vertex := formatVertex(point{x, y}, 5)
+			if values[x][y] <= 0 {
+				t.Errorf("%s has influence %d next to a lone blue dot", vertex, values[x][y])
+			}
+			if values[x][y] != values[4-x][y] || values[x][y] != values[y][x] {
+				t.Errorf("%s has influence %d, unlike its mirror images", vertex, values[x][y])
+			}
+			if opposite[x][y] != -values[x][y] {
+				t.Errorf("%s has influence %d for red, %d for blue", vertex, opposite[x][y], values[x][y])
+			}
+		}
+	}
+	if values[2][2] <= values[2][1] || values[2][1] <= values[2][0] {
+		t.Errorf("the influence does not fall off with the distance: %d, %d, %d", values[2][2], values[2][1], values[2][0])
+	}
+}
+
+// TestEstimateTerritoryWalls checks that each wall holds the edge behind it and that the
+// open middle between them belongs to nobody.
+func TestEstimateTerritoryWalls(t *testing.T) {
+	board, err := ParseText(`
+. X . . . O .
+. X . . . O .
+. X . . . O .
+. X . . . O .
+. X . . . O .
+. X . . . O .
+. X . . . O .
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owners := board.EstimateTerritory()
+	want := []cellState{blue, empty, empty, empty, empty, empty, red} // By column, dots have no owner
+	for x := range owners {
+		for y := range owners[x] {
+			if owners[x][y] != want[x] {
+				t.Errorf("%s is estimated for %s, want %s", formatVertex(point{x, y}, 7), owners[x][y], want[x])
+			}
+		}
+	}
+	if blueScore, redScore := board.ScoreEstimate(); blueScore != 14 || redScore != 14 {
+		t.Errorf("got the estimate %d-%d, want 14-14", blueScore, redScore)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+// ReadLadder reads out the ladder against the group at the given cell, which must be in atari.
+// The defender keeps extending at its last liberty and the attacker keeps giving atari. It
+// reports whether the attacker takes the group's last liberty and returns the moves played.
+func (b *Board) ReadLadder(x, y int) (works bool, sequence []move) {
+	group, ok := b.GroupAt(x, y)
+	if !ok || len(group.liberties) != 1 {
+		return false, nil
+	}
+	return b.readLadder(point{x, y}, group.color, nil)
+}
+
+// GroupsInAtari returns the groups of both colors left with a single liberty.
+func (b *Board) GroupsInAtari() []Group {
+	var groups []Group
+	for _, color := range []cellState{blue, red} {
+		for _, group := range b.Groups(color) {
+			if len(group.liberties) == 1 {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+// readLadder plays the defender's escape from atari, then tries both attacker ataris.
+func (b *Board) readLadder(target point, defender cellState, sequence []move) (bool, []move) {
+	// Every move fills a cell, so the reading always stops before the board is full
+	if len(sequence) >= b.size*b.size {
+		return false, sequence
+	}
+
+	group, _ := b.GroupAt(target.x, target.y)
+	escape := group.liberties[0]
+	next := b.Copy()
+	next.Play(defender, escape.x, escape.y)
+	sequence = append(sequence, move{color: defender, x: escape.x, y: escape.y})
+
+	group, _ = next.GroupAt(target.x, target.y)
+	switch {
+	case len(group.liberties) == 0:
+		return true, sequence
+	case len(group.liberties) == 1:
+		// Extending did not help, the attacker takes the last liberty
+		last := group.liberties[0]
+		return true, append(sequence, move{color: opponent(defender), x: last.x, y: last.y})
+	case len(group.liberties) > 2:
+		return false, sequence
+	}
+
+	// Two liberties: the attacker tries atari from either side
+	longest := sequence
+	for _, atari := range group.liberties {
+		attempt := next.Copy()
+		attempt.Play(opponent(defender), atari.x, atari.y)
+		chase := append(append([]move(nil), sequence...), move{color: opponent(defender), x: atari.x, y: atari.y})
+		if attempt.Liberties(target.x, target.y) != 1 {
+			continue
+		}
+
+		if works, result := attempt.readLadder(target, defender, chase); works {
+			return true, result
+		} else if len(result) > len(longest) {
+			longest = result
+		}
+	}
+	return false, longest
+}
+
+// opponent returns the other player's color.
+func opponent(color cellState) cellState {
+	if color == blue {
+		return red
+	}
+	return blue
+}
+
+// Additional methods for handling ladder functionalities
+// ...
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// The blue dot at C2 is in atari and runs towards the upper right corner.
+const ladderStart = `
+. . . . . . .
+. . . . . . .
+. . . . . . .
+. . . . . . .
+. . O . . . .
+. O X . . . .
+. . O O . . .
+`
+
+func TestReadLadder(t *testing.T) {
+	tests := []struct {
+		name     string
+		breaker  string // Vertex of a blue dot on the way, if any
+		works    bool
+		sequence string
+	}{
+		{"to the edge", "", true, "X D2, O E2, X D3, O D4, X E3, O F3, X E4, O E5, X F4, O F5, X G4, O G5, X G3, O G2"},
+		{"broken", "F5", false, "X D2, O E2, X D3, O D4, X E3, O F3, X E4, O E5, X F4"},
+	}
+	for _, test := range tests {
+		board, err := ParseText(ladderStart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.breaker != "" {
+			p, _ := parseVertex(test.breaker, board.Size())
+			board.Setup(blue, p.x, p.y)
+		}
+
+		works, sequence := board.ReadLadder(2, 5)
+		var moves []string
+		for _, m := range sequence {
+			symbol := "X"
+			if m.color == red {
+				symbol = "O"
+			}
+			moves = append(moves, symbol+" "+formatVertex(point{m.x, m.y}, board.Size()))
+		}
+		if got := strings.Join(moves, ", "); works != test.works || got != test.sequence {
+			t.Errorf("%s: got %v after %s, want %v after %s", test.name, works, got, test.works, test.sequence)
+		}
+	}
+}
+
+func TestReadLadderNeedsAtari(t *testing.T) {
+	board, err := ParseText(ladderStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if works, sequence := board.ReadLadder(1, 5); works || sequence != nil {
+		t.Errorf("read a ladder against a group with three liberties: %v, %v", works, sequence)
+	}
+	if works, sequence := board.ReadLadder(0, 0); works || sequence != nil {
+		t.Errorf("read a ladder on an empty cell: %v, %v", works, sequence)
+	}
+
+	groups := board.GroupsInAtari()
+	if len(groups) != 1 || groups[0].color != blue || groups[0].stones[0] != (point{2, 5}) {
+		t.Errorf("want the blue dot at C2 in atari, got %d groups", len(groups))
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Orders the library can be sorted in.
+const (
+	librarySortDate    = "Date"
+	librarySortPlayers = "Players"
+	librarySortSize    = "Size"
+	librarySortResult  = "Result"
+	librarySortFile    = "File"
+)
+
+var librarySortOrders = []string{librarySortDate, librarySortPlayers, librarySortSize, librarySortResult, librarySortFile}
+
+// LibraryConfig is the directory the library was last scanning.
+type LibraryConfig struct {
+	Directory string `json:"directory"`
+}
+
+// libraryConfigPath returns the library settings file next to the autosave file.
+func libraryConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-in-go", "library.json"), nil
+}
+
+// readLibraryConfig loads the library settings, without a directory the first time.
+func readLibraryConfig(path string) LibraryConfig {
+	var config LibraryConfig
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &config) // Broken settings are simply entered again
+	}
+	return config
+}
+
+// LibraryGame is a saved game found in the library directory.
+type LibraryGame struct {
+	path   string
+	tree   *GameTree
+	size   int
+	info   GameInfo
+	date   time.Time // Day the game was played, or when the file was saved if the record does not tell
+	result string    // Empty while the game goes on
+}
+
+// ScanLibrary loads the SGF games and autosave files found in dir and its subdirectories,
+// skipping hidden ones. Files that cannot be read as a game are only counted.
+func ScanLibrary(dir string) (games []LibraryGame, skipped int, err error) {
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			skipped++
+			return nil
+		}
+		if entry.IsDir() {
+			if path != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".sgf" && ext != ".json" {
+			return nil
+		}
+		game, err := LoadLibraryGame(path)
+		if err != nil {
+			skipped++
+			return nil
+		}
+		games = append(games, game)
+		return nil
+	})
+	return games, skipped, err
+}
+
+// LoadLibraryGame reads an SGF game or an autosave file, positioned at its first move.
+func LoadLibraryGame(path string) (LibraryGame, error) {
+	game := LibraryGame{path: path}
+
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var save autosave
+		if save, err = readAutosave(path); err == nil {
+			game.size = save.Size
+			game.tree, game.info, err = save.restore()
+		}
+	} else {
+		game.tree, game.size, game.info, err = LoadSGF(path)
+	}
+	if err != nil {
+		return LibraryGame{}, err
+	}
+	game.tree.GoTo(game.tree.root)
+
+	game.result, _ = game.info.Result(game.tree, game.size)
+	if len(game.info.date) >= len("2006-01-02") {
+		game.date, err = time.Parse("2006-01-02", game.info.date[:len("2006-01-02")])
+	}
+	if game.date.IsZero() || err != nil {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return LibraryGame{}, err
+		}
+		game.date = stat.ModTime()
+	}
+	return game, nil
+}
+
+// Players names both players, blue first.
+func (g LibraryGame) Players() string {
+	return g.info.blueName + " vs " + g.info.redName
+}
+
+// Details sums up the date, board size, result and file of the game.
+func (g LibraryGame) Details() string {
+	result := g.result
+	if result == "" {
+		result = "unfinished"
+	}
+	return fmt.Sprintf("%s · %dx%d · %s · %s", g.date.Format("Jan 2, 2006"), g.size, g.size, result, filepath.Base(g.path))
+}
+
+// Thumbnail draws the final position of the main line.
+func (g LibraryGame) Thumbnail() image.Image {
+	return newDiagram(g.tree, g.tree.mainLineEnd(), g.size).Image(DiagramOptions{})
+}
+
+// matches reports whether the players, details or file path contain the filter, ignoring case.
+func (g LibraryGame) matches(filter string) bool {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	text := strings.ToLower(g.Players() + " " + g.Details() + " " + g.path)
+	return strings.Contains(text, filter)
+}
+
+// FilterLibrary returns the games matching the filter in the given order, see librarySortOrders.
+func FilterLibrary(games []LibraryGame, filter, order string) []LibraryGame {
+	shown := []LibraryGame{}
+	for _, game := range games {
+		if game.matches(filter) {
+			shown = append(shown, game)
+		}
+	}
+
+	sort.SliceStable(shown, func(i, j int) bool {
+		a, b := shown[i], shown[j]
+		switch order {
+		case librarySortPlayers:
+			return strings.ToLower(a.Players()) < strings.ToLower(b.Players())
+		case librarySortSize:
+			return a.size < b.size
+		case librarySortResult:
+			if (a.result == "") != (b.result == "") {
+				return b.result == "" // Unfinished games last
+			}
+			return a.result < b.result
+		case librarySortFile:
+			return a.path < b.path
+		default:
+			return a.date.After(b.date) // Newest first
+		}
+	})
+	return shown
+}
+
+// Additional methods for handling library functionalities
+// ...
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"image"
+	"sync"
+)
+
+const libraryThumbnailSize = 64
+
+// LibraryWindow lists the games saved in a directory and opens them in the game window.
+type LibraryWindow struct {
+	window     fyne.Window
+	gameWindow *GameWindow
+	// mu guards the fields below, which the scan in the background replaces
+	mu         sync.Mutex
+	config     LibraryConfig
+	filter     string                 // Text of the filter entry
+	order      string                 // Sort order chosen
+	games      []LibraryGame          // Every game found in the directory
+	shown      []LibraryGame          // Games matching the filter, in the chosen order
+	thumbnails map[string]image.Image // Drawn once per file
+	// UI components
+	folderLabel *widget.Label
+	statusLabel *widget.Label
+	filterEntry *widget.Entry
+	sortSelect  *widget.Select
+	list        *widget.List
+}
+
+func NewLibraryWindow(app fyne.App, gameWindow *GameWindow) *LibraryWindow {
+	lw := &LibraryWindow{
+		window:      app.NewWindow("Go in Go: library"),
+		gameWindow:  gameWindow,
+		thumbnails:  make(map[string]image.Image),
+		folderLabel: widget.NewLabel(""),
+		statusLabel: widget.NewLabel(""),
+		filterEntry: widget.NewEntry(),
+	}
+	if path, err := libraryConfigPath(); err == nil {
+		lw.config = readLibraryConfig(path)
+	}
+
+	lw.filterEntry.SetPlaceHolder("Filter by player, date, size, result or file")
+	lw.filterEntry.OnChanged = func(filter string) {
+		lw.mu.Lock()
+		lw.filter = filter
+		lw.mu.Unlock()
+		lw.applyFilter()
+	}
+	lw.order = librarySortDate
+	lw.sortSelect = widget.NewSelect(librarySortOrders, nil)
+	lw.sortSelect.SetSelected(lw.order)
+	lw.sortSelect.OnChanged = func(order string) {
+		lw.mu.Lock()
+		lw.order = order
+		lw.mu.Unlock()
+		lw.applyFilter()
+	}
+	lw.list = lw.createList()
+
+	topBar := container.NewVBox(
+		container.NewHBox(
+			lw.folderLabel,
+			layout.NewSpacer(),
+			widget.NewButton("Choose folder...", lw.ShowFolderDialog),
+			widget.NewButton("Rescan", lw.Scan),
+		),
+		container.NewBorder(nil, nil, nil, lw.sortSelect, lw.filterEntry),
+	)
+	lw.window.SetContent(container.NewBorder(topBar, lw.statusLabel, nil, nil, lw.list))
+	lw.window.Resize(fyne.NewSize(480, 560))
+
+	return lw
+}
+
+// Show makes the library visible, asking for the directory the first time.
+func (lw *LibraryWindow) Show() {
+	lw.window.Show()
+	lw.mu.Lock()
+	directory := lw.config.Directory
+	lw.mu.Unlock()
+	if directory == "" {
+		lw.folderLabel.SetText("No folder chosen")
+		lw.ShowFolderDialog()
+		return
+	}
+	lw.Scan()
+}
+
+// ShowFolderDialog asks for the directory of saved games and scans it.
+func (lw *LibraryWindow) ShowFolderDialog() {
+	dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, lw.window)
+			return
+		}
+		if folder == nil {
+			return // The dialog was cancelled
+		}
+
+		lw.mu.Lock()
+		lw.config.Directory = folder.Path()
+		config := lw.config
+		lw.mu.Unlock()
+		if path, err := libraryConfigPath(); err == nil {
+			if err := writeConfigFile(path, config); err != nil {
+				fyne.LogError("Could not save the library settings", err)
+			}
+		}
+		lw.Scan()
+	}, lw.window)
+}
+
+// Scan reads the games of the directory again in the background. Only the reading is done
+// there, the games found replace the listed ones holding lw.mu.
+func (lw *LibraryWindow) Scan() {
+	lw.mu.Lock()
+	directory := lw.config.Directory
+	lw.mu.Unlock()
+	if directory == "" {
+		return
+	}
+	lw.folderLabel.SetText(directory)
+	lw.statusLabel.SetText("Scanning...")
+
+	go func() {
+		games, skipped, err := ScanLibrary(directory)
+		if !lw.setGames(directory, games, err) {
+			return // Another folder was chosen meanwhile
+		}
+		if err != nil {
+			lw.statusLabel.SetText("")
+			dialog.ShowError(err, lw.window)
+			return
+		}
+		lw.applyFilter()
+
+		status := fmt.Sprintf("%d games", len(games))
+		if skipped > 0 {
+			status += fmt.Sprintf(", %d unreadable files skipped", skipped)
+		}
+		lw.statusLabel.SetText(status)
+	}()
+}
+
+// setGames replaces the games listed by the result of scanning the given directory, unless
+// another directory was chosen since. It reports whether the directory is still the chosen one.
+func (lw *LibraryWindow) setGames(directory string, games []LibraryGame, err error) bool {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if directory != lw.config.Directory {
+		return false
+	}
+	if err == nil {
+		lw.games = games
+		lw.thumbnails = make(map[string]image.Image)
+	}
+	return true
+}
+
+func (lw *LibraryWindow) applyFilter() {
+	lw.mu.Lock()
+	lw.shown = FilterLibrary(lw.games, lw.filter, lw.order)
+	lw.mu.Unlock()
+	// The list reads the games back, so it is refreshed without the lock
+	lw.list.UnselectAll()
+	lw.list.Refresh()
+}
+
+// shownGames returns the games listed.
+func (lw *LibraryWindow) shownGames() []LibraryGame {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.shown
+}
+
+func (lw *LibraryWindow) createList() *widget.List {
+	list := widget.NewList(
+		func() int {
+			return len(lw.shownGames())
+		},
+		func() fyne.CanvasObject {
+			thumbnail := canvas.NewImageFromImage(nil)
+			thumbnail.FillMode = canvas.ImageFillContain
+			thumbnail.SetMinSize(fyne.NewSize(libraryThumbnailSize, libraryThumbnailSize))
+			players := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			return container.NewHBox(thumbnail, container.NewVBox(players, widget.NewLabel("")))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			shown := lw.shownGames()
+			if id >= len(shown) {
+				return
+			}
+			game := shown[id]
+			row := item.(*fyne.Container)
+			labels := row.Objects[1].(*fyne.Container)
+
+			thumbnail := row.Objects[0].(*canvas.Image)
+			thumbnail.Image = lw.thumbnail(game)
+			thumbnail.Refresh()
+			labels.Objects[0].(*widget.Label).SetText(game.Players())
+			labels.Objects[1].(*widget.Label).SetText(game.Details())
+		},
+	)
+
+	list.OnSelected = func(id widget.ListItemID) {
+		shown := lw.shownGames()
+		if id >= len(shown) {
+			return
+		}
+		lw.Open(shown[id])
+		list.Unselect(id) // So the same game can be opened again
+	}
+	return list
+}
+
+func (lw *LibraryWindow) thumbnail(game LibraryGame) image.Image {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if img, ok := lw.thumbnails[game.path]; ok {
+		return img
+	}
+	img := game.Thumbnail()
+	lw.thumbnails[game.path] = img
+	return img
+}
+
+// Open loads the game afresh from its file into the game window, at its first move.
+func (lw *LibraryWindow) Open(game LibraryGame) {
+	fresh, err := LoadLibraryGame(game.path)
+	if err != nil {
+		dialog.ShowError(err, lw.window)
+		return
+	}
+	lw.gameWindow.do(func() { lw.gameWindow.LoadGame(fresh.tree, fresh.size, fresh.info) })
+	lw.gameWindow.window.RequestFocus()
+}
+
+// Additional methods for handling LibraryWindow functionalities
+// ...
@@ -1,6 +1,31 @@
 package main
 
+import (
+	"fmt"
+	"os"
+)
+
 func main() {
+	// Commands run without opening a window
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "gtp": // Speak the Go Text Protocol on stdin and stdout
+			err = RunGTPCommand(os.Args[2:], os.Stdin, os.Stdout)
+		case "solve":
+			err = RunSolveCommand(os.Args[2:], os.Stdout)
+		case "selfplay": // Bot against bot matches
+			err = RunSelfPlayCommand(os.Args[2:], os.Stdout)
+		default:
+			err = fmt.Errorf("unknown command %q, use gtp, solve or selfplay", os.Args[1])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	gameApp := NewGameApp()
 	gameApp.Run()
 }
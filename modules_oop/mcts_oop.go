@@ -0,0 +1,280 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Parameters of the tree search.
+const (
+	mctsExploration  = 0.7  // Weight of the UCT exploration term
+	mctsRAVEEquiv    = 1000 // Visits after which RAVE and plain statistics weigh the same
+	mctsDefaultCount = 3000 // Playouts per move when no budget is configured
+)
+
+// MCTSConfig sets the budget of the tree search. With both limits set, the first one reached ends it.
+type MCTSConfig struct {
+	playouts  int           // Playouts per move, 0 for no limit
+	timeLimit time.Duration // Thinking time per move, 0 for no limit
+	workers   int           // Goroutines searching in parallel, 0 for one per CPU
+	rave      bool          // Share statistics between moves played in any order (AMAF)
+}
+
+// MCTSBot chooses moves with Monte Carlo tree search using UCT. Each worker grows its own
+// tree from the same position and their root statistics are added up at the end.
+type MCTSBot struct {
+	config    MCTSConfig
+	seeds     *rand.Rand
+	cancelled atomic.Bool // Set by Cancel, searches end after their current playout
+}
+
+func NewMCTSBot(config MCTSConfig) *MCTSBot {
+	if config.playouts == 0 && config.timeLimit == 0 {
+		config.playouts = mctsDefaultCount
+	}
+	if config.workers == 0 {
+		config.workers = runtime.NumCPU()
+	}
+	return &MCTSBot{
+		config: config,
+		seeds:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Cancel ends the searches of the bot early, now and from then on. The moves searched
+// so far are still returned.
+func (b *MCTSBot) Cancel() {
+	b.cancelled.Store(true)
+}
+
+// mctsNode is a position of the search tree, reached by the move of its color.
+type mctsNode struct {
+	cell       point
+	color      cellState // Player who moved to this node
+	parent     *mctsNode
+	children   []*mctsNode
+	untried    []point // Moves not expanded yet, in random order
+	visits     int
+	wins       float64 // Playouts won by color, draws counting half
+	amafVisits int     // Playouts where color played this cell later, see MCTSConfig.rave
+	amafWins   float64
+}
+
+func (b *MCTSBot) GenMove(board *Board, color cellState) move {
+	candidates := b.Candidates(board, color)
+	if len(candidates) == 0 {
+		return move{color: color, pass: true}
+	}
+	best := candidates[0].cell
+	return move{color: color, x: best.x, y: best.y}
+}
+
+// MCTSCandidate is a move considered by the search with its statistics over all workers.
+type MCTSCandidate struct {
+	cell    point
+	visits  int
+	winRate float64 // Share of the playouts through the move won by the player to move
+}
+
+// Candidates searches the position and returns the moves tried at the root, searched the
+// most first. The first one is the move the bot plays, the most robust choice.
+func (b *MCTSBot) Candidates(board *Board, color cellState) []MCTSCandidate {
+	if len(board.LegalMoves()) == 0 {
+		return nil
+	}
+
+	var deadline time.Time
+	if b.config.timeLimit > 0 {
+		deadline = time.Now().Add(b.config.timeLimit)
+	}
+
+	roots := make([]*mctsNode, b.config.workers)
+	var wg sync.WaitGroup
+	for i := range roots {
+		playouts := 0
+		if b.config.playouts > 0 {
+			// Share the budget, the first workers taking the remainder
+			playouts = b.config.playouts / b.config.workers
+			if i < b.config.playouts%b.config.workers {
+				playouts++
+			}
+			if playouts == 0 {
+				continue
+			}
+		}
+
+		random := rand.New(rand.NewSource(b.seeds.Int63()))
+		wg.Add(1)
+		go func(i, playouts int) {
+			defer wg.Done()
+			roots[i] = b.search(board, color, playouts, deadline, random)
+		}(i, playouts)
+	}
+	wg.Wait()
+
+	// Add up the root statistics of the workers, keeping the order moves were first found in
+	var candidates []MCTSCandidate
+	wins := make(map[point]float64)
+	index := make(map[point]int)
+	for _, root := range roots {
+		if root == nil {
+			continue
+		}
+		for _, child := range root.children {
+			i, ok := index[child.cell]
+			if !ok {
+				i = len(candidates)
+				index[child.cell] = i
+				candidates = append(candidates, MCTSCandidate{cell: child.cell})
+			}
+			candidates[i].visits += child.visits
+			wins[child.cell] += child.wins
+		}
+	}
+	for i := range candidates {
+		candidates[i].winRate = wins[candidates[i].cell] / float64(candidates[i].visits)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].visits > candidates[j].visits })
+	return candidates
+}
+
+// search grows a tree until the worker's share of playouts is done or the deadline passes.
+func (b *MCTSBot) search(board *Board, color cellState, playouts int, deadline time.Time, random *rand.Rand) *mctsNode {
+	root := &mctsNode{color: opponent(color), untried: shuffled(board.LegalMoves(), random)}
+
+	for i := 0; playouts == 0 || i < playouts; i++ {
+		if b.cancelled.Load() || (!deadline.IsZero() && time.Now().After(deadline)) {
+			break
+		}
+
+		// Selection
+		node, position := root, board.Copy()
+		var path []*mctsNode
+		for len(node.untried) == 0 && len(node.children) > 0 {
+			node = b.selectChild(node)
+			position.Play(node.color, node.cell.x, node.cell.y)
+			path = append(path, node)
+		}
+
+		// Expansion, unless the game is over here
+		if _, decided := position.DecidedWinner(); !decided && len(node.untried) > 0 {
+			cell := node.untried[len(node.untried)-1]
+			node.untried = node.untried[:len(node.untried)-1]
+
+			child := &mctsNode{cell: cell, color: opponent(node.color), parent: node}
+			position.Play(child.color, cell.x, cell.y)
+			child.untried = shuffled(position.LegalMoves(), random)
+			node.children = append(node.children, child)
+			node = child
+			path = append(path, node)
+		}
+
+		winner, playoutMoves := playout(position, random)
+		b.update(root, path, playoutMoves, winner)
+	}
+	return root
+}
+
+// selectChild picks the child with the best UCT value, blended with its RAVE value if enabled.
+func (b *MCTSBot) selectChild(node *mctsNode) *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	logVisits := math.Log(float64(node.visits))
+
+	for _, child := range node.children {
+		value := child.wins / float64(child.visits)
+		if b.config.rave && child.amafVisits > 0 {
+			beta := math.Sqrt(mctsRAVEEquiv / (3*float64(node.visits) + mctsRAVEEquiv))
+			value = (1-beta)*value + beta*child.amafWins/float64(child.amafVisits)
+		}
+		value += mctsExploration * math.Sqrt(logVisits/float64(child.visits))
+
+		if value > bestValue {
+			best, bestValue = child, value
+		}
+	}
+	return best
+}
+
+// update adds the playout result to the nodes searched and, with RAVE, to the siblings
+// whose move the same player made later in the playout.
+func (b *MCTSBot) update(root *mctsNode, path []*mctsNode, playoutMoves []move, winner cellState) {
+	for _, node := range append([]*mctsNode{root}, path...) {
+		node.visits++
+		node.wins += mctsScore(node.color, winner)
+	}
+	if !b.config.rave {
+		return
+	}
+
+	// A cell is played at most once per game, so the moves after a node are known by their cell
+	after := make(map[point]cellState)
+	for _, m := range playoutMoves {
+		after[point{m.x, m.y}] = m.color
+	}
+	for i := len(path); i >= 0; i-- {
+		node := root
+		if i > 0 {
+			node = path[i-1]
+		}
+		for _, child := range node.children {
+			if after[child.cell] == child.color {
+				child.amafVisits++
+				child.amafWins += mctsScore(child.color, winner)
+			}
+		}
+		if i > 0 {
+			after[node.cell] = node.color
+		}
+	}
+}
+
+func mctsScore(color, winner cellState) float64 {
+	switch winner {
+	case color:
+		return 1
+	case empty:
+		return 0.5 // A draw
+	default:
+		return 0
+	}
+}
+
+// playout plays random moves until the game is decided. It returns the winner, empty for
+// a draw, and the moves played. Every dot played fills the clusters it encloses, so the score
+// is the dots alone and is only checked once a player has most of them.
+func playout(board *Board, random *rand.Rand) (cellState, []move) {
+	var moves []move
+	half := board.size * board.size / 2
+	for _, cell := range shuffled(board.LegalMoves(), random) {
+		if board.Count(blue) > half || board.Count(red) > half {
+			if winner, decided := board.DecidedWinner(); decided {
+				return winner, moves
+			}
+		}
+		if board.At(cell.x, cell.y) != empty {
+			continue // Filled since the list was made
+		}
+		color := board.ToMove()
+		board.Play(color, cell.x, cell.y)
+		moves = append(moves, move{color: color, x: cell.x, y: cell.y})
+	}
+
+	if winner, decided := board.DecidedWinner(); decided {
+		return winner, moves
+	}
+	return empty, moves // The board is full and evenly shared
+}
+
+func shuffled(cells []point, random *rand.Rand) []point {
+	random.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	return cells
+}
+
+// Additional methods for handling MCTS functionalities
+// ...
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// testMCTSBot searches with one worker and a fixed seed so that its moves are repeatable.
+func testMCTSBot(playouts int) *MCTSBot {
+	return &MCTSBot{
+		config: MCTSConfig{playouts: playouts, workers: 1},
+		seeds:  rand.New(rand.NewSource(1)),
+	}
+}
+
+func TestMCTSBotPassesOnFullBoard(t *testing.T) {
+	board, err := ParseText("X O\nO X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := testMCTSBot(100).GenMove(board, red); !m.pass || m.color != red {
+		t.Errorf("got %+v on a full board, want a red pass", m)
+	}
+}
+
+// TestMCTSBotSharesThePlayouts checks that the workers search the whole budget between them,
+// each playout going through one of the moves at the root.
+func TestMCTSBotSharesThePlayouts(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		bot := testMCTSBot(200)
+		bot.config.workers = workers
+		total := 0
+		for _, candidate := range bot.Candidates(NewBoard(4), blue) {
+			total += candidate.visits
+		}
+		if total != 200 {
+			t.Errorf("%d workers searched %d playouts, want 200", workers, total)
+		}
+	}
+}
+
+// TestMCTSBotSealsTheRace checks that the search finds the move that wins outright:
+// E3 encloses the top two rows for blue and keeps red from closing the bottom ones.
+func TestMCTSBotSealsTheRace(t *testing.T) {
+	board, err := ParseText(`
+. . . . .
+X X X X .
+. . . . .
+O O O O .
+. . . . .
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board.Pass(blue) // Filling starts with the next dot
+	board.Pass(red)
+
+	candidates := testMCTSBot(2000).Candidates(board, blue)
+	if len(candidates) == 0 {
+		t.Fatal("no moves searched")
+	}
+	best := candidates[0]
+	if vertex := formatVertex(best.cell, 5); vertex != "E3" || best.winRate < 0.95 {
+		t.Errorf("got %s winning %.2f of its playouts, want E3", vertex, best.winRate)
+	}
+	if m := testMCTSBot(2000).GenMove(board, blue); m.color != blue || m.pass || m.x != best.cell.x || m.y != best.cell.y {
+		t.Errorf("GenMove played %+v, not the most searched move", m)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMoveListGolden exports the main line of each testdata/movelist record as CSV and JSON.
+func TestMoveListGolden(t *testing.T) {
+	names, inputs := goldenInputs(t, "movelist", ".sgf")
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			tree, size, _, err := ReadSGF(strings.NewReader(inputs[name]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			records := NewMoveList(tree, size)
+
+			var got strings.Builder
+			if err := WriteMoveListCSV(&got, records); err != nil {
+				t.Fatal(err)
+			}
+			got.WriteString("\n")
+			if err := WriteMoveListJSON(&got, records); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, name, got.String())
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"sync/atomic"
+	"time"
+)
+
+// Autoplay speeds offered, as the time each move stays on the board.
+var replaySpeeds = map[string]time.Duration{
+	"0.5 s": 500 * time.Millisecond,
+	"1 s":   time.Second,
+	"2 s":   2 * time.Second,
+	"4 s":   4 * time.Second,
+}
+
+// ReplayBar steps through the variation shown, see GameTree.Variation, with buttons,
+// a move slider and autoplay. It also starts the analysis of the variation. Like the rest of
+// the game window, its actions run holding the game lock, see GameWindow.do.
+type ReplayBar struct {
+	container   *fyne.Container
+	gameWindow  *GameWindow
+	slider      *widget.Slider
+	moveLabel   *widget.Label
+	playButton  *widget.Button
+	speedSelect *widget.Select
+	autoplay    chan struct{} // Closed to stop autoplay, nil when not playing
+	following   atomic.Bool   // Set while the slider follows the game, so it does not navigate
+}
+
+// NewReplayBar creates the bar, hidden until a game ends or is loaded.
+func NewReplayBar(gameWindow *GameWindow) *ReplayBar {
+	r := &ReplayBar{
+		gameWindow: gameWindow,
+		slider:     widget.NewSlider(0, 0),
+		moveLabel:  widget.NewLabel(""),
+	}
+	r.slider.Step = 1
+	r.slider.OnChanged = func(value float64) {
+		if !r.following.Load() {
+			gameWindow.do(func() { r.GoTo(int(value)) })
+		}
+	}
+	r.playButton = widget.NewButtonWithIcon("", theme.MediaPlayIcon(), gameWindow.locked(r.ToggleAutoplay))
+	r.speedSelect = widget.NewSelect([]string{"0.5 s", "1 s", "2 s", "4 s"}, nil)
+	r.speedSelect.SetSelected("1 s")
+	r.speedSelect.OnChanged = func(string) {
+		gameWindow.do(func() {
+			if r.autoplay != nil {
+				// Restart at the new speed
+				r.StopAutoplay()
+				r.StartAutoplay()
+			}
+		})
+	}
+
+	buttons := container.NewHBox(
+		widget.NewButtonWithIcon("", theme.MediaSkipPreviousIcon(), gameWindow.locked(func() { r.GoTo(0) })),
+		widget.NewButtonWithIcon("", theme.MediaFastRewindIcon(), gameWindow.locked(func() { r.Step((*GameTree).Back) })),
+		r.playButton,
+		widget.NewButtonWithIcon("", theme.MediaFastForwardIcon(), gameWindow.locked(func() { r.Step((*GameTree).Forward) })),
+		widget.NewButtonWithIcon("", theme.MediaSkipNextIcon(), gameWindow.locked(func() { r.GoTo(r.lastMove()) })),
+	)
+	analyzeButton := widget.NewButtonWithIcon("Analyze", theme.ComputerIcon(), gameWindow.locked(gameWindow.ShowAnalysis))
+	r.container = container.NewBorder(nil, nil, buttons, container.NewHBox(r.moveLabel, r.speedSelect, analyzeButton), r.slider)
+	r.container.Hide()
+	return r
+}
+
+// Show makes the bar visible.
+func (r *ReplayBar) Show() {
+	r.Update()
+	r.container.Show()
+}
+
+// Hide stops autoplay and hides the bar.
+func (r *ReplayBar) Hide() {
+	r.StopAutoplay()
+	r.container.Hide()
+}
+
+// Update moves the slider to the position shown.
+func (r *ReplayBar) Update() {
+	tree := r.gameWindow.gameTree
+	number := tree.current.depth()
+
+	r.following.Store(true)
+	r.slider.Max = float64(max(r.lastMove(), 1)) // A slider needs some room even before the first move
+	r.slider.SetValue(float64(number))
+	r.slider.Refresh()
+	r.following.Store(false)
+
+	r.moveLabel.SetText(fmt.Sprintf("Move %d / %d", number, r.lastMove()))
+}
+
+// GoTo shows the position after the given move of the variation.
+func (r *ReplayBar) GoTo(number int) {
+	nodes := r.gameWindow.gameTree.Variation()
+	number = min(max(number, 0), len(nodes)-1)
+	if nodes[number] == r.gameWindow.gameTree.current {
+		return
+	}
+	r.gameWindow.gameTree.GoTo(nodes[number])
+	r.gameWindow.ReplayGameTree()
+}
+
+// Step applies a game tree step, stopping autoplay when it is done by hand.
+func (r *ReplayBar) Step(step func(t *GameTree) bool) {
+	r.StopAutoplay()
+	r.gameWindow.navigate(step)
+}
+
+// ToggleAutoplay starts or pauses autoplay. The computer may have the move where it pauses.
+func (r *ReplayBar) ToggleAutoplay() {
+	if r.autoplay != nil {
+		r.StopAutoplay()
+		r.gameWindow.playComputerTurn()
+		return
+	}
+	r.StartAutoplay()
+}
+
+// StartAutoplay plays the moves one after another at the chosen speed, from the first move
+// when the end is already shown. Only the ticker runs in the background, the moves are
+// shown holding the game lock.
+func (r *ReplayBar) StartAutoplay() {
+	if r.autoplay != nil {
+		return
+	}
+	if r.gameWindow.gameTree.current.depth() == r.lastMove() {
+		r.GoTo(0)
+	}
+
+	stop := make(chan struct{})
+	r.autoplay = stop
+	r.playButton.SetIcon(theme.MediaPauseIcon())
+
+	ticker := time.NewTicker(replaySpeeds[r.speedSelect.Selected])
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				r.gameWindow.do(func() {
+					if r.autoplay != stop {
+						return // Stopped while waiting for the lock
+					}
+					if !r.gameWindow.gameTree.Forward() {
+						r.StopAutoplay()
+						r.gameWindow.playComputerTurn() // The computer may have the move at the end
+						return
+					}
+					r.gameWindow.ReplayGameTree()
+				})
+			}
+		}
+	}()
+}
+
+// StopAutoplay pauses autoplay on the move shown.
+func (r *ReplayBar) StopAutoplay() {
+	if r.autoplay == nil {
+		return
+	}
+	close(r.autoplay)
+	r.autoplay = nil
+	r.playButton.SetIcon(theme.MediaPlayIcon())
+}
+
+// lastMove returns the number of the last move of the variation shown.
+func (r *ReplayBar) lastMove() int {
+	return len(r.gameWindow.gameTree.Variation()) - 1
+}
+
+// Additional methods for handling replay functionalities
+// ...
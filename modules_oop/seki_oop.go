@@ -0,0 +1,86 @@
+package main
+
+// SekiPoints returns the liberties shared by groups living in seki: a blue and a red group
+// sharing liberties that neither side wants to take first, see isSeki.
+func (b *Board) SekiPoints() []point {
+	var seki []point
+	seen := make(map[point]bool)
+
+	redGroups := b.Groups(red)
+	for _, blueGroup := range b.Groups(blue) {
+		for _, redGroup := range redGroups {
+			shared := sharedLiberties(blueGroup, redGroup)
+			if len(shared) == 0 || !b.isSeki(blueGroup, redGroup, shared) {
+				continue
+			}
+			for _, cell := range shared {
+				if !seen[cell] {
+					seen[cell] = true
+					seki = append(seki, cell)
+				}
+			}
+		}
+	}
+	return seki
+}
+
+// isSeki reports whether both groups have two liberties or more, and a dot of either color
+// on any shared liberty, after the clusters it fills, leaves its own group with one liberty
+// at most, which the other side then takes. The shared cells border both colors, so no fill
+// hands them to either player and they are nobody's territory.
+func (b *Board) isSeki(first, second Group, shared []point) bool {
+	if len(first.liberties) < 2 || len(second.liberties) < 2 {
+		return false // A group in atari is not in seki, it is about to be captured
+	}
+
+	for _, group := range []Group{first, second} {
+		for _, cell := range shared {
+			next := b.Copy()
+			next.Play(group.color, cell.x, cell.y)
+			if next.Liberties(cell.x, cell.y) > 1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ScoreEstimate counts each player's dots plus the empty cells they are likely to own.
+// Cells shared in seki belong to nobody.
+func (b *Board) ScoreEstimate() (blueScore, redScore int) {
+	owners := b.EstimateTerritory()
+	for x := range b.cells {
+		for y := range b.cells[x] {
+			owner := b.cells[x][y]
+			if owner == empty {
+				owner = owners[x][y]
+			}
+
+			switch owner {
+			case blue:
+				blueScore++
+			case red:
+				redScore++
+			}
+		}
+	}
+	return blueScore, redScore
+}
+
+func sharedLiberties(first, second Group) []point {
+	liberties := make(map[point]bool)
+	for _, liberty := range first.liberties {
+		liberties[liberty] = true
+	}
+
+	var shared []point
+	for _, liberty := range second.liberties {
+		if liberties[liberty] {
+			shared = append(shared, liberty)
+		}
+	}
+	return shared
+}
+
+// Additional methods for handling seki functionalities
+// ...
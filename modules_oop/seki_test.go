@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+// The blue and red groups share two liberties, and a dot on either leaves its group with one.
+const sekiPosition = `
+X . O
+X . O
+X X O
+`
+
+func TestSekiPoints(t *testing.T) {
+	board, err := ParseText(sekiPosition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := formatPoints(board.SekiPoints(), board.Size()); got != "[B2 B3]" {
+		t.Errorf("got the seki points %s, want [B2 B3]", got)
+	}
+
+	// Once a side takes a shared liberty, the other takes the last one
+	board.Play(blue, 1, 1)
+	if seki := board.SekiPoints(); len(seki) != 0 {
+		t.Errorf("after blue B2, still in seki at %s", formatPoints(seki, board.Size()))
+	}
+}
+
+func TestSekiIsNobodysTerritory(t *testing.T) {
+	board, err := ParseText(sekiPosition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board.Pass(red)
+
+	if blueScore, redScore := board.Score(); blueScore != 4 || redScore != 3 {
+		t.Errorf("got the score %d-%d, want the dots 4-3", blueScore, redScore)
+	}
+	if blueScore, redScore := board.ScoreEstimate(); blueScore != 4 || redScore != 3 {
+		t.Errorf("got the estimate %d-%d, want the dots 4-3", blueScore, redScore)
+	}
+	owners := board.EstimateTerritory()
+	for _, cell := range board.SekiPoints() {
+		if owner := owners[cell.x][cell.y]; owner != empty {
+			t.Errorf("the seki point %s is estimated for %s", formatVertex(cell, board.Size()), owner)
+		}
+	}
+}
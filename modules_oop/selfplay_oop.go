@@ -0,0 +1,275 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// selfPlayZ is the normal quantile of the 95% confidence intervals.
+const selfPlayZ = 1.96
+
+// SelfPlayConfig describes a match between two bots, named as on the command line.
+type SelfPlayConfig struct {
+	black, white string // Bots of blue, moving first, and red
+	mcts         MCTSConfig
+	games        int
+	size         int
+	komi         float64
+	parallel     int    // Games played at once, 0 for one per CPU
+	sgfDir       string // Directory every game is saved to, empty to keep none
+	alternate    bool   // Swap the colors of the bots every other game
+}
+
+// SelfPlayGame is the outcome of one game of a match.
+type SelfPlayGame struct {
+	winner  cellState // empty for a draw
+	moves   int
+	margin  float64 // Blue's score minus red's, less komi, see Board.Score
+	swapped bool    // The white bot of the config played blue
+}
+
+// colorOf returns the color played by the black bot of the config, or by the white one.
+func (g SelfPlayGame) colorOf(blackBot bool) cellState {
+	if blackBot != g.swapped {
+		return blue
+	}
+	return red
+}
+
+// RunSelfPlayCommand plays a match between two bots without a window and prints its statistics.
+func RunSelfPlayCommand(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("selfplay", flag.ContinueOnError)
+	config := SelfPlayConfig{}
+	flags.StringVar(&config.black, "black", "mcts", "bot playing black (blue), moving first: random, greedy, mcts or solver")
+	flags.StringVar(&config.white, "white", "greedy", "bot playing white (red): random, greedy, mcts or solver")
+	flags.IntVar(&config.games, "games", 100, "number of games")
+	flags.IntVar(&config.size, "size", 9, "board size")
+	flags.Float64Var(&config.komi, "komi", 0, "dots given to white")
+	flags.IntVar(&config.parallel, "parallel", 0, "games played at once, 0 for one per CPU")
+	flags.StringVar(&config.sgfDir, "sgf", "", "directory to save every game to as SGF")
+	flags.BoolVar(&config.alternate, "alternate", false, "swap the colors of the bots every other game")
+	mcts := mctsFlags(flags)
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return nil // The usage has been printed
+	} else if err != nil {
+		return err
+	}
+	config.mcts = *mcts
+
+	if config.games < 1 || config.size < 1 || config.size > maxBoardSize {
+		return fmt.Errorf("need at least one game on a board of 1 to %d", maxBoardSize)
+	}
+	for _, name := range []string{config.black, config.white} {
+		if _, err := NewBotByFlag(name, config.mcts); err != nil {
+			return err
+		}
+	}
+	if config.sgfDir != "" {
+		if err := os.MkdirAll(config.sgfDir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	games, err := PlaySelfPlayMatch(config)
+	if err != nil {
+		return err
+	}
+	writeSelfPlayReport(out, config, games, time.Since(start))
+	return nil
+}
+
+// PlaySelfPlayMatch plays the games of a match, several at once. Bots keep state like random
+// sources and transposition tables, so each goroutine creates its own.
+func PlaySelfPlayMatch(config SelfPlayConfig) ([]SelfPlayGame, error) {
+	parallel := config.parallel
+	if parallel <= 0 {
+		parallel = runtime.NumCPU()
+	}
+	if config.mcts.workers == 0 {
+		// The games share the CPUs, each MCTS bot searches on its part of them
+		config.mcts.workers = max(1, runtime.NumCPU()/parallel)
+	}
+
+	games := make([]SelfPlayGame, config.games)
+	errs := make([]error, parallel)
+	numbers := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for number := range numbers {
+				if errs[i] != nil {
+					continue // Drain the remaining games
+				}
+				games[number], errs[i] = playSelfPlayGame(config, number)
+			}
+		}(i)
+	}
+	for number := range games {
+		numbers <- number
+	}
+	close(numbers)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return games, nil
+}
+
+// playSelfPlayGame plays one game until it is decided, komi included, the board is full or
+// both bots pass, and saves it if asked to.
+func playSelfPlayGame(config SelfPlayConfig, number int) (SelfPlayGame, error) {
+	game := SelfPlayGame{swapped: config.alternate && number%2 == 1}
+	names := map[cellState]string{blue: config.black, red: config.white}
+	if game.swapped {
+		names[blue], names[red] = config.white, config.black
+	}
+
+	bots := make(map[cellState]Bot)
+	for color, name := range names {
+		bot, err := NewBotByFlag(name, config.mcts)
+		if err != nil {
+			return SelfPlayGame{}, err
+		}
+		bots[color] = bot
+	}
+
+	board, tree := NewBoard(config.size), NewGameTree()
+	passes := 0
+	for passes < 2 {
+		if _, decided := board.DecidedWinnerWithKomi(config.komi); decided || board.IsFull() {
+			break
+		}
+
+		color := board.ToMove()
+		m := bots[color].GenMove(board.Copy(), color)
+		m.color = color
+		if m.pass {
+			passes++
+		} else if board.At(m.x, m.y) != empty {
+			return SelfPlayGame{}, fmt.Errorf("game %d: %s played on the occupied cell %s",
+				number+1, color, formatVertex(point{m.x, m.y}, config.size))
+		} else {
+			passes = 0
+		}
+		board.Apply(m)
+		tree.Play(m)
+	}
+
+	blueScore, redScore := board.Score()
+	game.moves = len(tree.Moves())
+	game.margin = float64(blueScore-redScore) - config.komi
+	switch {
+	case game.margin > 0:
+		game.winner = blue
+	case game.margin < 0:
+		game.winner = red
+	}
+
+	if config.sgfDir != "" {
+		info := defaultGameInfo()
+		info.blueName, info.redName, info.komi = names[blue], names[red], config.komi
+		if _, decided := board.DecidedWinnerWithKomi(config.komi); !decided && !board.IsFull() {
+			info.result = marginResult(game.margin) // Ended by passes, which the board cannot tell
+		}
+		path := filepath.Join(config.sgfDir, fmt.Sprintf("game-%04d.sgf", number+1))
+		if err := SaveSGF(path, tree, config.size, info); err != nil {
+			return SelfPlayGame{}, err
+		}
+	}
+	return game, nil
+}
+
+// writeSelfPlayReport prints the win rates, the game lengths and the distribution of margins.
+// The bots are named by their flag, -black or -white, whichever color they played.
+func writeSelfPlayReport(out io.Writer, config SelfPlayConfig, games []SelfPlayGame, elapsed time.Duration) {
+	draws, moves := 0, 0
+	margins := make(map[float64]int)
+	for _, game := range games {
+		if game.winner == empty {
+			draws++
+		}
+		moves += game.moves
+		if game.swapped {
+			margins[-game.margin]++ // Margins are those of the -black bot
+		} else {
+			margins[game.margin]++
+		}
+	}
+
+	n := len(games)
+	fmt.Fprintf(out, "%d games on %dx%d in %s, komi %g\n", n, config.size, config.size, elapsed.Round(time.Second), config.komi)
+	for _, side := range []struct {
+		label, name string
+		blackBot    bool
+	}{{"Black", config.black, true}, {"White", config.white, false}} {
+		wins, asBlack, winsAsBlack := 0, 0, 0
+		for _, game := range games {
+			color := game.colorOf(side.blackBot)
+			if game.winner == color {
+				wins++
+			}
+			if color == blue {
+				asBlack++
+				if game.winner == blue {
+					winsAsBlack++
+				}
+			}
+		}
+
+		// Draws count as half a win
+		score := (float64(wins) + float64(draws)/2) / float64(n)
+		low, high := wilsonInterval(score, n)
+		if config.alternate {
+			fmt.Fprintf(out, "-%s bot (%s): %d wins (%d of %d as black), score %.1f%% (95%% CI %.1f%%-%.1f%%)\n",
+				strings.ToLower(side.label), side.name, wins, winsAsBlack, asBlack, score*100, low*100, high*100)
+		} else {
+			fmt.Fprintf(out, "%s (%s): %d wins, score %.1f%% (95%% CI %.1f%%-%.1f%%)\n",
+				side.label, side.name, wins, score*100, low*100, high*100)
+		}
+	}
+	fmt.Fprintf(out, "Draws: %d\n", draws)
+	fmt.Fprintf(out, "Average length: %.1f moves\n", float64(moves)/float64(n))
+
+	if config.alternate {
+		fmt.Fprintln(out, "Margins (-black bot minus -white bot, komi counting for whichever played white):")
+	} else {
+		fmt.Fprintln(out, "Margins (black minus white):")
+	}
+	var values []float64
+	for margin := range margins {
+		values = append(values, margin)
+	}
+	sort.Float64s(values)
+	for _, margin := range values {
+		count := margins[margin]
+		bar := strings.Repeat("#", int(math.Ceil(float64(count)*40/float64(n))))
+		fmt.Fprintf(out, "%+7g %5d %s\n", margin, count, bar)
+	}
+}
+
+// wilsonInterval returns the Wilson score interval of a proportion observed over n games.
+func wilsonInterval(p float64, n int) (low, high float64) {
+	z2 := selfPlayZ * selfPlayZ
+	count := float64(n)
+	center := (p + z2/(2*count)) / (1 + z2/count)
+	spread := selfPlayZ * math.Sqrt(p*(1-p)/count+z2/(4*count*count)) / (1 + z2/count)
+	return center - spread, center + spread
+}
+
+// Additional methods for handling self-play functionalities
+// ...
@@ -0,0 +1,559 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rulesName identifies the simplified filling rules in saved games.
+const rulesName = "Go in Go filling"
+
+// maxSGFSize is the largest board SGF coordinates can describe.
+const maxSGFSize = 52
+
+// Private properties keeping the timing of moves played here: when the move was played, in
+// RFC 3339 format, and the game clock then, in seconds. Other programs ignore them.
+const (
+	sgfPlayedAt = "PLAYED"
+	sgfClock    = "CLOCK"
+)
+
+// GameInfo holds the game properties written to saved games.
+type GameInfo struct {
+	blueName string // Blue moves first and is saved as black
+	redName  string
+	komi     float64
+	rules    string
+	date     string // Day the game was played, as YYYY-MM-DD
+	result   string // Result the board cannot tell, like a resignation, or that of a loaded record
+	// Time settings: the main time of each player, 0 when the game is untimed, and the
+	// overtime system, e.g. "5x30 byo-yomi". The game clock only counts up, so games played
+	// here are untimed; loaded records keep their own settings.
+	mainTime time.Duration
+	overtime string
+}
+
+// defaultGameInfo describes a hot-seat game with the simplified rules, which have no komi.
+func defaultGameInfo() GameInfo {
+	return GameInfo{
+		blueName: "Blue",
+		redName:  "Red",
+		rules:    rulesName,
+		date:     time.Now().Format("2006-01-02"),
+	}
+}
+
+// SaveSGF writes the game to a file, see WriteSGF.
+func SaveSGF(path string, tree *GameTree, size int, info GameInfo) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteSGF(file, tree, size, info); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteSGF writes the game tree with all its variations as an SGF (FF[4]) record.
+// The result is only written once the game has ended, see GameInfo.Result. Boards larger
+// than SGF coordinates can describe are refused before anything is written.
+func WriteSGF(w io.Writer, tree *GameTree, size int, info GameInfo) error {
+	if size > maxSGFSize {
+		return fmt.Errorf("sgf: boards larger than %dx%d cannot be saved", maxSGFSize, maxSGFSize)
+	}
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "(;FF[4]GM[1]CA[UTF-8]AP[Go in Go:1.0]SZ[%d]", size)
+	fmt.Fprintf(out, "PB[%s]PW[%s]", sgfEscape(info.blueName), sgfEscape(info.redName))
+	fmt.Fprintf(out, "KM[%g]RU[%s]", info.komi, sgfEscape(info.rules))
+	if info.date != "" {
+		fmt.Fprintf(out, "DT[%s]", sgfEscape(info.date))
+	}
+	fmt.Fprintf(out, "TM[%g]", info.mainTime.Seconds())
+	if info.overtime != "" {
+		fmt.Fprintf(out, "OT[%s]", sgfEscape(info.overtime))
+	}
+	if result, ok := info.Result(tree, size); ok {
+		fmt.Fprintf(out, "RE[%s]", result)
+	}
+	writeSGFExtras(out, tree.root)
+
+	writeSGFVariations(out, tree.root)
+	out.WriteString(")\n")
+	return out.Flush()
+}
+
+// writeSGFVariations writes the continuations of a node, each alternative in its own brackets.
+func writeSGFVariations(out *bufio.Writer, node *GameNode) {
+	for len(node.children) == 1 {
+		node = node.children[0]
+		writeSGFNode(out, node)
+	}
+
+	for _, child := range node.children {
+		out.WriteString("\n(")
+		writeSGFNode(out, child)
+		writeSGFVariations(out, child)
+		out.WriteString(")")
+	}
+}
+
+// writeSGFNode writes the move of a node and its timing. Its setup dots follow in a node of
+// their own, since SGF does not allow mixing moves and setup in one node.
+func writeSGFNode(out *bufio.Writer, node *GameNode) {
+	out.WriteString(sgfMove(node.move))
+	if node.comment != "" {
+		fmt.Fprintf(out, "C[%s]", sgfEscape(node.comment))
+	}
+	if !node.playedAt.IsZero() {
+		fmt.Fprintf(out, "%s[%s]%s[%g]", sgfPlayedAt, node.playedAt.Format(time.RFC3339Nano), sgfClock, node.clock.Seconds())
+	}
+	if len(node.setup) > 0 {
+		out.WriteString(";")
+		writeSGFSetup(out, node.setup)
+	}
+}
+
+// writeSGFExtras writes the setup dots and the comment of the root node.
+func writeSGFExtras(out *bufio.Writer, node *GameNode) {
+	writeSGFSetup(out, node.setup)
+	if node.comment != "" {
+		fmt.Fprintf(out, "C[%s]", sgfEscape(node.comment))
+	}
+}
+
+func writeSGFSetup(out *bufio.Writer, setup []move) {
+	for _, property := range []struct {
+		name  string
+		color cellState
+	}{{"AB", blue}, {"AW", red}} {
+		var values []string
+		for _, stone := range setup {
+			if stone.color == property.color {
+				values = append(values, fmt.Sprintf("[%c%c]", sgfCoordinate(stone.x), sgfCoordinate(stone.y)))
+			}
+		}
+		if len(values) > 0 {
+			out.WriteString(property.name + strings.Join(values, ""))
+		}
+	}
+}
+
+// sgfMove writes a move as a node, blue playing black. Passes have an empty value.
+func sgfMove(m move) string {
+	player := "B"
+	if m.color == red {
+		player = "W"
+	}
+	if m.pass {
+		return fmt.Sprintf(";%s[]", player)
+	}
+	return fmt.Sprintf(";%s[%c%c]", player, sgfCoordinate(m.x), sgfCoordinate(m.y))
+}
+
+// sgfCoordinate writes a line index as a letter, lower case first, then upper case.
+func sgfCoordinate(index int) rune {
+	if index < 26 {
+		return 'a' + rune(index)
+	}
+	return 'A' + rune(index-26)
+}
+
+// gameResult scores a finished game in SGF notation, e.g. B+3 when blue wins by three cells,
+// see Board.Score. It reports false while the game is still going on, see DecidedWinnerWithKomi.
+func gameResult(board *Board, komi float64) (string, bool) {
+	if _, decided := board.DecidedWinnerWithKomi(komi); !decided && !board.IsFull() {
+		return "", false
+	}
+
+	blueScore, redScore := board.Score()
+	return marginResult(float64(blueScore-redScore) - komi), true
+}
+
+// marginResult writes blue's lead in dots, less komi, in SGF notation.
+func marginResult(margin float64) string {
+	switch {
+	case margin > 0:
+		return fmt.Sprintf("B+%g", margin)
+	case margin < 0:
+		return fmt.Sprintf("W+%g", -margin)
+	default:
+		return "0"
+	}
+}
+
+// Result returns the result of a game: that of the board once the main line has reached the end
+// of the game, otherwise the recorded one. It reports false while the game goes on.
+func (info GameInfo) Result(tree *GameTree, size int) (string, bool) {
+	if result, ok := gameResult(tree.BoardAt(tree.mainLineEnd(), size), info.komi); ok {
+		return result, true
+	}
+	return info.result, info.result != ""
+}
+
+// sgfEscape protects the characters that end or escape an SGF text value.
+func sgfEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `]`, `\]`).Replace(text)
+}
+
+// sgfNode holds the properties of one SGF node.
+type sgfNode map[string][]string
+
+// sgfTree is a sequence of SGF nodes followed by its variations.
+type sgfTree struct {
+	nodes      []sgfNode
+	variations []*sgfTree
+}
+
+// LoadSGF reads a game from a file, see ReadSGF.
+func LoadSGF(path string) (*GameTree, int, GameInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, GameInfo{}, err
+	}
+	defer file.Close()
+
+	return ReadSGF(file)
+}
+
+// ReadSGF reads the first game of an SGF collection, with its variations, setup dots and
+// comments. It returns the game tree positioned at the start, the board size and the game info.
+func ReadSGF(r io.Reader) (*GameTree, int, GameInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, GameInfo{}, err
+	}
+
+	parser := &sgfParser{data: string(data)}
+	parsed, err := parser.parseTree()
+	if err != nil {
+		return nil, 0, GameInfo{}, err
+	}
+	if len(parsed.nodes) == 0 {
+		return nil, 0, GameInfo{}, errors.New("sgf: the game has no nodes")
+	}
+
+	rootProperties := parsed.nodes[0]
+	if game := rootProperties.value("GM"); game != "" && game != "1" {
+		return nil, 0, GameInfo{}, fmt.Errorf("sgf: GM[%s] is not a game of Go", game)
+	}
+
+	size := 19 // The SGF default for Go
+	if value := rootProperties.value("SZ"); value != "" {
+		size, err = strconv.Atoi(value)
+		if err != nil || size < 1 || size > maxBoardSize {
+			return nil, 0, GameInfo{}, fmt.Errorf("sgf: unsupported board size %q", value)
+		}
+	}
+
+	info := defaultGameInfo()
+	if name := rootProperties.value("PB"); name != "" {
+		info.blueName = name
+	}
+	if name := rootProperties.value("PW"); name != "" {
+		info.redName = name
+	}
+	if komi := rootProperties.value("KM"); komi != "" {
+		if info.komi, err = strconv.ParseFloat(komi, 64); err != nil {
+			return nil, 0, GameInfo{}, fmt.Errorf("sgf: invalid komi %q", komi)
+		}
+	}
+	if rules := rootProperties.value("RU"); rules != "" {
+		info.rules = rules
+	}
+	if mainTime := rootProperties.value("TM"); mainTime != "" {
+		seconds, err := strconv.ParseFloat(mainTime, 64)
+		if err != nil || seconds < 0 {
+			return nil, 0, GameInfo{}, fmt.Errorf("sgf: invalid main time %q", mainTime)
+		}
+		info.mainTime = time.Duration(seconds * float64(time.Second))
+	}
+	info.overtime = rootProperties.value("OT")
+	info.date = rootProperties.value("DT")
+	info.result = rootProperties.value("RE")
+
+	tree := NewGameTree()
+	if err := addSGFTree(tree, tree.root, parsed, size); err != nil {
+		return nil, 0, GameInfo{}, err
+	}
+	tree.GoTo(tree.root)
+	return tree, size, info, nil
+}
+
+// addSGFTree adds the nodes of an SGF tree after the given node, then its variations.
+func addSGFTree(tree *GameTree, node *GameNode, parsed *sgfTree, size int) error {
+	for _, properties := range parsed.nodes {
+		m, hasMove, err := properties.move(size)
+		if err != nil {
+			return err
+		}
+		if hasMove {
+			tree.GoTo(node)
+			node = tree.Play(m)
+			if err := readSGFTiming(node, properties); err != nil {
+				return err
+			}
+		}
+
+		// Setup dots and comments of nodes without a move belong to the previous move
+		for _, property := range []struct {
+			name  string
+			color cellState
+		}{{"AB", blue}, {"AW", red}} {
+			points, err := properties.points(property.name, size)
+			if err != nil {
+				return err
+			}
+			for _, p := range points {
+				node.setup = append(node.setup, move{color: property.color, x: p.x, y: p.y})
+			}
+		}
+		if comment := properties.value("C"); comment != "" {
+			if node.comment != "" {
+				node.comment += "\n"
+			}
+			node.comment += comment
+		}
+	}
+
+	for _, variation := range parsed.variations {
+		if err := addSGFTree(tree, node, variation, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSGFTiming sets when the move of a node was played and the game clock then, as written
+// by writeSGFNode. Moves of other programs are left untimed.
+func readSGFTiming(node *GameNode, properties sgfNode) error {
+	playedAt := properties.value(sgfPlayedAt)
+	if playedAt == "" {
+		return nil
+	}
+	when, err := time.Parse(time.RFC3339Nano, playedAt)
+	if err != nil {
+		return fmt.Errorf("sgf: invalid move time %q", playedAt)
+	}
+	clock := properties.value(sgfClock)
+	seconds, err := strconv.ParseFloat(clock, 64)
+	if err != nil || seconds < 0 {
+		return fmt.Errorf("sgf: invalid game clock %q", clock)
+	}
+	node.playedAt = when
+	node.clock = time.Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// value returns the first value of a property, or an empty string.
+func (n sgfNode) value(name string) string {
+	if values := n[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// move returns the B or W move of the node. Empty values and "tt" on small boards are passes.
+func (n sgfNode) move(size int) (move, bool, error) {
+	for _, property := range []struct {
+		name  string
+		color cellState
+	}{{"B", blue}, {"W", red}} {
+		values, ok := n[property.name]
+		if !ok {
+			continue
+		}
+
+		value := ""
+		if len(values) > 0 {
+			value = values[0]
+		}
+		if value == "" || (value == "tt" && size <= 19) {
+			return move{color: property.color, pass: true}, true, nil
+		}
+
+		p, err := parseSGFPoint(value, size)
+		if err != nil {
+			return move{}, false, err
+		}
+		return move{color: property.color, x: p.x, y: p.y}, true, nil
+	}
+	return move{}, false, nil
+}
+
+// points returns the cells listed by a property, expanding compressed "aa:cc" rectangles.
+func (n sgfNode) points(name string, size int) ([]point, error) {
+	var points []point
+	for _, value := range n[name] {
+		corners := strings.SplitN(value, ":", 2)
+		from, err := parseSGFPoint(corners[0], size)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if len(corners) == 2 {
+			if to, err = parseSGFPoint(corners[1], size); err != nil {
+				return nil, err
+			}
+		}
+
+		for x := min(from.x, to.x); x <= max(from.x, to.x); x++ {
+			for y := min(from.y, to.y); y <= max(from.y, to.y); y++ {
+				points = append(points, point{x, y})
+			}
+		}
+	}
+	return points, nil
+}
+
+func parseSGFPoint(value string, size int) (point, error) {
+	if len(value) != 2 {
+		return point{}, fmt.Errorf("sgf: invalid point %q", value)
+	}
+
+	x, y := sgfIndex(value[0]), sgfIndex(value[1])
+	if x < 0 || y < 0 || x >= size || y >= size {
+		return point{}, fmt.Errorf("sgf: point %q is outside the board", value)
+	}
+	return point{x, y}, nil
+}
+
+func sgfIndex(letter byte) int {
+	switch {
+	case letter >= 'a' && letter <= 'z':
+		return int(letter - 'a')
+	case letter >= 'A' && letter <= 'Z':
+		return int(letter-'A') + 26
+	default:
+		return -1
+	}
+}
+
+// sgfParser reads the SGF text format: trees in brackets, nodes starting with a semicolon
+// and properties with one or more values in square brackets.
+type sgfParser struct {
+	data string
+	pos  int
+}
+
+// parseTree reads a bracketed game tree, skipping any text before it.
+func (p *sgfParser) parseTree() (*sgfTree, error) {
+	start := strings.IndexByte(p.data[p.pos:], '(')
+	if start < 0 {
+		return nil, errors.New("sgf: no game found")
+	}
+	p.pos += start + 1
+
+	tree := &sgfTree{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, errors.New("sgf: unexpected end of file")
+		}
+
+		switch p.data[p.pos] {
+		case ';':
+			p.pos++
+			node, err := p.parseNode()
+			if err != nil {
+				return nil, err
+			}
+			tree.nodes = append(tree.nodes, node)
+		case '(':
+			variation, err := p.parseTree()
+			if err != nil {
+				return nil, err
+			}
+			tree.variations = append(tree.variations, variation)
+		case ')':
+			p.pos++
+			return tree, nil
+		default:
+			return nil, fmt.Errorf("sgf: unexpected %q at offset %d", p.data[p.pos], p.pos)
+		}
+	}
+}
+
+// parseNode reads the properties following a semicolon.
+func (p *sgfParser) parseNode() (sgfNode, error) {
+	node := make(sgfNode)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) || !isSGFIdentLetter(p.data[p.pos]) {
+			return node, nil
+		}
+
+		// Old files may write identifiers like AddBlack, only the capitals count
+		var name strings.Builder
+		for p.pos < len(p.data) && isSGFIdentLetter(p.data[p.pos]) {
+			if p.data[p.pos] >= 'A' && p.data[p.pos] <= 'Z' {
+				name.WriteByte(p.data[p.pos])
+			}
+			p.pos++
+		}
+
+		p.skipSpace()
+		if p.pos >= len(p.data) || p.data[p.pos] != '[' {
+			return nil, fmt.Errorf("sgf: property %s has no value", name.String())
+		}
+		for p.pos < len(p.data) && p.data[p.pos] == '[' {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			node[name.String()] = append(node[name.String()], value)
+			p.skipSpace()
+		}
+	}
+}
+
+// parseValue reads a bracketed value, resolving escapes and soft line breaks.
+func (p *sgfParser) parseValue() (string, error) {
+	p.pos++ // Skip the opening bracket
+
+	var value strings.Builder
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+
+		switch c {
+		case ']':
+			return value.String(), nil
+		case '\\':
+			if p.pos >= len(p.data) {
+				break
+			}
+			escaped := p.data[p.pos]
+			p.pos++
+			if escaped == '\n' || escaped == '\r' {
+				continue // An escaped line break is removed
+			}
+			value.WriteByte(escaped)
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", errors.New("sgf: unterminated property value")
+}
+
+func (p *sgfParser) skipSpace() {
+	for p.pos < len(p.data) && strings.IndexByte(" \t\r\n", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func isSGFIdentLetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// Additional methods for handling SGF functionalities
+// ...
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestSGFGolden reads each testdata/sgf record, writes it back and shows the position at
+// the end of its main line. Writing the record read back must give the same text.
+func TestSGFGolden(t *testing.T) {
+	names, inputs := goldenInputs(t, "sgf", ".sgf")
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			tree, size, info, err := ReadSGF(strings.NewReader(inputs[name]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var written strings.Builder
+			if err := WriteSGF(&written, tree, size, info); err != nil {
+				t.Fatal(err)
+			}
+
+			again, againSize, againInfo, err := ReadSGF(strings.NewReader(written.String()))
+			if err != nil {
+				t.Fatalf("reading the record written: %v", err)
+			}
+			var rewritten strings.Builder
+			if err := WriteSGF(&rewritten, again, againSize, againInfo); err != nil {
+				t.Fatal(err)
+			}
+			if rewritten.String() != written.String() {
+				t.Errorf("the record changed when read back:\n%s\n%s", written.String(), rewritten.String())
+			}
+
+			var got strings.Builder
+			got.WriteString(written.String())
+			fmt.Fprintf(&got, "\nmain line: %d moves, %d nodes\n", len(tree.MainLine()), len(tree.nodes))
+			got.WriteString(tree.BoardAt(tree.mainLineEnd(), size).Text(TextOptions{coordinates: true}))
+			checkGolden(t, name, got.String())
+		})
+	}
+}
+
+func TestWriteSGFBoardSize(t *testing.T) {
+	var sgf strings.Builder
+	if err := WriteSGF(&sgf, NewGameTree(), maxSGFSize+1, defaultGameInfo()); err == nil || sgf.Len() > 0 {
+		t.Errorf("a %dx%d board was written: %v %q", maxSGFSize+1, maxSGFSize+1, err, sgf.String())
+	}
+
+	// The largest board played keeps its last line
+	tree := NewGameTree()
+	tree.Play(move{color: blue, x: maxBoardSize - 1, y: maxBoardSize - 1})
+	if err := WriteSGF(&sgf, tree, maxBoardSize, defaultGameInfo()); err != nil {
+		t.Fatal(err)
+	}
+	read, size, _, err := ReadSGF(strings.NewReader(sgf.String()))
+	if err != nil || size != maxBoardSize {
+		t.Fatalf("reading the %dx%d board back: size %d, %v", maxBoardSize, maxBoardSize, size, err)
+	}
+	if moves := read.MainLine(); len(moves) != 1 || moves[0].x != maxBoardSize-1 || moves[0].y != maxBoardSize-1 {
+		t.Errorf("the corner move was read back as %+v", moves)
+	}
+}
+
+func TestReadSGFErrors(t *testing.T) {
+	for _, record := range []string{
+		"",
+		"(;GM[2]SZ[9])",
+		"(;SZ[26])",
+		"(;SZ[60])",
+		"(;SZ[9];B[zz])",
+		"(;SZ[9]TM[soon])",
+		"(;SZ[9];B[aa]",
+	} {
+		if _, _, _, err := ReadSGF(strings.NewReader(record)); err == nil {
+			t.Errorf("ReadSGF(%q) succeeded, want an error", record)
+		}
+	}
+}
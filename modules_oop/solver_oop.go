@@ -0,0 +1,497 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// maxSolverSize is the largest board the solver accepts: positions are keyed by 2 bits
+// per cell in a uint64, and larger boards would take far too long anyway.
+const maxSolverSize = 5
+
+// solverBotNodes is the search budget of the solver bot, a few seconds.
+const solverBotNodes = 2_000_000
+
+// solverBotEmpty is the number of empty cells from which the solver bot tries to solve the
+// position: the empty 4x4 board takes well under its budget, a 5x5 board with a few more
+// empty cells would only exhaust it.
+const solverBotEmpty = 16
+
+// errSolverBudget is returned when a position needs more nodes than allowed. The table keeps
+// what was solved, so trying again with a larger budget starts from there.
+var errSolverBudget = errors.New("solver: the position needs a larger search")
+
+// solverTableBits sets the size of the transposition table: 1<<21 buckets of two entries,
+// 64 MB. When two positions meet in a bucket, the table keeps the one that took the most
+// work to solve and the latest one, so a full table never has to start over.
+const solverTableBits = 21
+
+// Bounds stored in the transposition table next to a value.
+const (
+	solverExact = iota
+	solverLower // The value is at least the one stored
+	solverUpper // The value is at most the one stored
+)
+
+// solverEntry is a position in the transposition table, see solverLayout.key.
+type solverEntry struct {
+	key   uint64
+	work  uint32 // Positions searched to find the value, 0 for an unused entry
+	value int8
+	bound int8
+}
+
+// solverBucket holds the entry that took the most work to find and the latest other one.
+type solverBucket [2]solverEntry
+
+// Solution is the outcome of a position when both players play perfectly.
+type Solution struct {
+	winner cellState // Empty for a draw
+	best   move      // A move reaching the outcome for the player to move, a pass when the game is over
+	nodes  int       // Positions searched
+}
+
+// Solver searches positions exactly with alpha-beta and a transposition table shared by
+// the positions it solves on one board size. Mirrored and rotated positions share their entry.
+type Solver struct {
+	table    []solverBucket // Made by the first Solve, for the size of its board
+	size     int            // Board size of the table entries
+	nodes    int
+	maxNodes int // Positions searched before giving up, 0 for no limit
+}
+
+func NewSolver() *Solver {
+	return &Solver{}
+}
+
+// Solve finds the outcome of the position with perfect play, and the best move of the player to move.
+func (s *Solver) Solve(board *Board) (Solution, error) {
+	if board.size > maxSolverSize {
+		return Solution{}, fmt.Errorf("solver: boards larger than %dx%d cannot be solved", maxSolverSize, maxSolverSize)
+	}
+
+	if s.table == nil || board.size != s.size {
+		s.table = make([]solverBucket, 1<<solverTableBits)
+		s.size = board.size
+	}
+	s.nodes = 0
+	layout := newSolverLayout(board.size)
+	position := layout.position(board)
+	color := board.ToMove()
+	solution := Solution{best: move{color: color, pass: true}}
+	if value, over := layout.result(position); over {
+		solution.winner = solverWinner(color, value)
+		return solution, nil
+	}
+
+	// Search the first level here to keep the move reaching the value
+	bestValue := -2
+	for _, child := range layout.children(position, nil) {
+		value := -s.negamax(layout, child.position, -1, -bestValue)
+		if value > bestValue {
+			bestValue = value
+			solution.best = move{color: color, x: child.cell % board.size, y: child.cell / board.size}
+		}
+		if bestValue == 1 {
+			break // Nothing beats a win
+		}
+	}
+
+	if s.exhausted() {
+		return Solution{}, errSolverBudget
+	}
+	solution.winner = solverWinner(color, bestValue)
+	solution.nodes = s.nodes
+	return solution, nil
+}
+
+// negamax returns 1 if the player to move wins, -1 if they lose and 0 for a draw.
+func (s *Solver) negamax(layout *solverLayout, position solverPosition, alpha, beta int) int {
+	s.nodes++
+	if value, over := layout.result(position); over {
+		return value
+	}
+	if s.exhausted() {
+		return 0 // Unwind, the caller reports errSolverBudget
+	}
+
+	key := layout.key(position)
+	startNodes := s.nodes
+	if entry, ok := s.lookup(key); ok {
+		switch {
+		case entry.bound == solverExact:
+			return int(entry.value)
+		case entry.bound == solverLower && int(entry.value) >= beta:
+			return int(entry.value)
+		case entry.bound == solverUpper && int(entry.value) <= alpha:
+			return int(entry.value)
+		}
+	}
+
+	originalAlpha := alpha
+	best := -2
+	var buffer [maxSolverSize * maxSolverSize]solverChild
+	for _, child := range layout.children(position, buffer[:0]) {
+		value := -s.negamax(layout, child.position, -beta, -alpha)
+		if s.exhausted() {
+			return 0 // The value is unfinished and must not be stored
+		}
+		best = max(best, value)
+		alpha = max(alpha, value)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	entry := solverEntry{key: key, value: int8(best), bound: solverExact}
+	if best <= originalAlpha {
+		entry.bound = solverUpper
+	} else if best >= beta {
+		entry.bound = solverLower
+	}
+	entry.work = uint32(min(int64(s.nodes-startNodes), math.MaxUint32))
+	s.store(entry)
+	return best
+}
+
+// bucket returns the bucket of a key, spreading the keys with a multiplicative hash.
+func (s *Solver) bucket(key uint64) *solverBucket {
+	return &s.table[key*0x9e3779b97f4a7c15>>(64-solverTableBits)]
+}
+
+// lookup returns the entry of a position, if the table still has it.
+func (s *Solver) lookup(key uint64) (solverEntry, bool) {
+	for _, entry := range s.bucket(key) {
+		if entry.work != 0 && entry.key == key {
+			return entry, true
+		}
+	}
+	return solverEntry{}, false
+}
+
+// store adds an entry. One that took at least as much work as the first of its bucket
+// takes its place, moving the first down, anything else replaces the second.
+func (s *Solver) store(entry solverEntry) {
+	bucket := s.bucket(entry.key)
+	switch {
+	case bucket[0].key == entry.key || bucket[0].work == 0:
+		bucket[0] = entry
+	case entry.work >= bucket[0].work:
+		bucket[1], bucket[0] = bucket[0], entry
+	default:
+		bucket[1] = entry
+	}
+}
+
+// exhausted reports whether the search went over its budget.
+func (s *Solver) exhausted() bool {
+	return s.maxNodes > 0 && s.nodes > s.maxNodes
+}
+
+func solverWinner(color cellState, value int) cellState {
+	switch value {
+	case 1:
+		return color
+	case -1:
+		return opponent(color)
+	default:
+		return empty
+	}
+}
+
+// solverPosition is a compact board for the search: one bit per cell and color, cell x, y
+// being bit y*size+x. It follows the same rules as Board, in a fraction of the time.
+type solverPosition struct {
+	blue, red uint32
+	next      cellState
+	filling   bool // Whether clusters are filled yet, which starts with the second move
+}
+
+// solverLayout holds the masks and symmetries of a board size.
+type solverLayout struct {
+	size     int
+	full     uint32
+	notLeft  uint32 // Cells with a neighbour on their left
+	notRight uint32
+	// Cells each set of cells of a row is moved to by each mirror or rotation
+	symmetries [8][maxSolverSize][1 << maxSolverSize]uint32
+	centrality [maxSolverSize * maxSolverSize]int // Higher for cells closer to the center
+}
+
+func newSolverLayout(size int) *solverLayout {
+	l := &solverLayout{size: size}
+	n := size - 1
+	transforms := [8]func(x, y int) (int, int){
+		func(x, y int) (int, int) { return x, y },
+		func(x, y int) (int, int) { return n - x, y },
+		func(x, y int) (int, int) { return x, n - y },
+		func(x, y int) (int, int) { return n - x, n - y },
+		func(x, y int) (int, int) { return y, x },
+		func(x, y int) (int, int) { return n - y, x },
+		func(x, y int) (int, int) { return y, n - x },
+		func(x, y int) (int, int) { return n - y, n - x },
+	}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			bit := uint32(1) << (y*size + x)
+			l.full |= bit
+			if x > 0 {
+				l.notLeft |= bit
+			}
+			if x < n {
+				l.notRight |= bit
+			}
+			l.centrality[y*size+x] = 2*n - abs(2*x-n) - abs(2*y-n)
+			for i, transform := range transforms {
+				tx, ty := transform(x, y)
+				for row := range l.symmetries[i][y] {
+					if row>>x&1 != 0 {
+						l.symmetries[i][y][row] |= 1 << (ty*size + tx)
+					}
+				}
+			}
+		}
+	}
+	return l
+}
+
+// position converts a board.
+func (l *solverLayout) position(board *Board) solverPosition {
+	p := solverPosition{next: board.ToMove(), filling: board.moves > 0}
+	for y := 0; y < l.size; y++ {
+		for x := 0; x < l.size; x++ {
+			switch board.At(x, y) {
+			case blue:
+				p.blue |= 1 << (y*l.size + x)
+			case red:
+				p.red |= 1 << (y*l.size + x)
+			}
+		}
+	}
+	return p
+}
+
+// neighbours returns the cells next to any cell of the set.
+func (l *solverLayout) neighbours(cells uint32) uint32 {
+	return ((cells&l.notRight)<<1 | (cells&l.notLeft)>>1 | cells<<l.size | cells>>l.size) & l.full
+}
+
+// play places a dot of the player to move and fills the clusters it encloses, like Board.Play.
+// It returns the number of cells filled.
+func (l *solverLayout) play(p *solverPosition, cell int) int {
+	if p.next == blue {
+		p.blue |= 1 << cell
+	} else {
+		p.red |= 1 << cell
+	}
+	p.next = opponent(p.next)
+	if !p.filling {
+		p.filling = true // The next move fills
+		return 0
+	}
+
+	filled := 0
+	remaining := l.full &^ (p.blue | p.red)
+	for remaining != 0 {
+		region := remaining & -remaining
+		for {
+			grown := region | l.neighbours(region)&remaining
+			if grown == region {
+				break
+			}
+			region = grown
+		}
+		remaining &^= region
+
+		borders := l.neighbours(region)
+		touchesBlue, touchesRed := borders&p.blue != 0, borders&p.red != 0
+		if touchesBlue && !touchesRed {
+			p.blue |= region
+		} else if touchesRed && !touchesBlue {
+			p.red |= region
+		} else {
+			continue
+		}
+		filled += bits.OnesCount32(region)
+	}
+	return filled
+}
+
+// result scores a finished game for the player to move, see Board.Score and Board.DecidedWinner.
+func (l *solverLayout) result(p solverPosition) (int, bool) {
+	half := l.size * l.size / 2
+	blueCount, redCount := bits.OnesCount32(p.blue), bits.OnesCount32(p.red)
+	if p.filling {
+		// The clusters enclosed by the player to move alone are filled by their next dot
+		if p.next == blue {
+			blueCount += bits.OnesCount32(l.enclosed(p.blue, p.red))
+		} else {
+			redCount += bits.OnesCount32(l.enclosed(p.red, p.blue))
+		}
+	}
+	if blueCount <= half && redCount <= half && p.blue|p.red != l.full {
+		return 0, false
+	}
+
+	winner := empty
+	if blueCount > redCount {
+		winner = blue
+	} else if redCount > blueCount {
+		winner = red
+	}
+	switch winner {
+	case p.next:
+		return 1, true
+	case empty:
+		return 0, true
+	default:
+		return -1, true
+	}
+}
+
+// enclosed returns the empty cells of the clusters bordered by the own color and not the other.
+func (l *solverLayout) enclosed(own, other uint32) uint32 {
+	var cells uint32
+	remaining := l.full &^ (own | other)
+	for remaining != 0 {
+		region := remaining & -remaining
+		for {
+			grown := region | l.neighbours(region)&remaining
+			if grown == region {
+				break
+			}
+			region = grown
+		}
+		remaining &^= region
+
+		if borders := l.neighbours(region); borders&own != 0 && borders&other == 0 {
+			cells |= region
+		}
+	}
+	return cells
+}
+
+// key encodes the position, the smallest code among its 8 symmetries.
+func (l *solverLayout) key(p solverPosition) uint64 {
+	var state uint64
+	if p.next == red {
+		state |= 1
+	}
+	if p.filling {
+		state |= 2
+	}
+
+	best := ^uint64(0)
+	rowMask := uint32(1)<<l.size - 1
+	for i := range l.symmetries {
+		var blueBits, redBits uint32
+		for y, rows := range l.symmetries[i][:l.size] {
+			blueBits |= rows[p.blue>>(y*l.size)&rowMask]
+			redBits |= rows[p.red>>(y*l.size)&rowMask]
+		}
+		best = min(best, state|uint64(blueBits)<<2|uint64(redBits)<<(2+l.size*l.size))
+	}
+	return best
+}
+
+type solverChild struct {
+	cell     int
+	position solverPosition
+	filled   int
+}
+
+// children plays every legal move and appends the positions to the given slice, those
+// filling the most cells first and then those closest to the center, so that good moves
+// cut the search early.
+func (l *solverLayout) children(p solverPosition, children []solverChild) []solverChild {
+	free := l.full &^ (p.blue | p.red)
+	for free != 0 {
+		cell := bits.TrailingZeros32(free)
+		free &= free - 1
+
+		child := solverChild{cell: cell, position: p}
+		child.filled = l.play(&child.position, cell)
+		children = append(children, child)
+	}
+	// An insertion sort, which does not allocate
+	for i := 1; i < len(children); i++ {
+		for j := i; j > 0 && l.before(children[j], children[j-1]); j-- {
+			children[j], children[j-1] = children[j-1], children[j]
+		}
+	}
+	return children
+}
+
+// before reports whether a move is tried before another, see children.
+func (l *solverLayout) before(a, b solverChild) bool {
+	if a.filled != b.filled {
+		return a.filled > b.filled
+	}
+	return l.centrality[a.cell] > l.centrality[b.cell]
+}
+
+// SolverBot plays perfectly once the solver can finish within its budget, which on 5x5 takes
+// a few dots, and plays like the MCTS bot until then and on larger boards. Its solver keeps
+// the table from move to move.
+type SolverBot struct {
+	solver   *Solver
+	fallback Bot
+}
+
+func NewSolverBot() *SolverBot {
+	solver := NewSolver()
+	solver.maxNodes = solverBotNodes
+	return &SolverBot{solver: solver, fallback: NewMCTSBot(strongMCTSConfig)}
+}
+
+func (b *SolverBot) GenMove(board *Board, color cellState) move {
+	empties := board.size*board.size - board.Count(blue) - board.Count(red)
+	if board.size > maxSolverSize || empties > solverBotEmpty {
+		return b.fallback.GenMove(board, color)
+	}
+	if board.ToMove() != color {
+		// Asked for the other player's move, e.g. by genmove, which solves that player's turn
+		board = board.Copy()
+		board.next = color
+	}
+	solution, err := b.solver.Solve(board)
+	if err != nil {
+		return b.fallback.GenMove(board, color)
+	}
+	return solution.best
+}
+
+// RunSolveCommand runs the solve command line: go-in-go solve [-size n]. It prints the outcome
+// of perfect play from the empty board, for every size up to n.
+func RunSolveCommand(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("solve", flag.ContinueOnError)
+	maxSize := flags.Int("size", 4, fmt.Sprintf("largest board solved, at most %d", maxSolverSize))
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return nil // The usage has been printed
+	} else if err != nil {
+		return err
+	}
+
+	solver := NewSolver()
+	for size := 1; size <= *maxSize; size++ {
+		solution, err := solver.Solve(NewBoard(size))
+		if err != nil {
+			return err
+		}
+
+		outcome := "a draw"
+		if solution.winner != empty {
+			outcome = solution.winner.String() + " wins"
+		}
+		fmt.Fprintf(out, "%dx%d: %s, opening at %s (searched %d positions)\n",
+			size, size, outcome, formatVertex(point{solution.best.x, solution.best.y}, size), solution.nodes)
+	}
+	return nil
+}
+
+// Additional methods for handling solver functionalities
+// ...
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestSolverLayoutAgreesWithBoard plays random games on every size the solver takes and
+// checks that its compact positions follow Board.Play and end and score the game as
+// DecidedWinner and Score do.
+func TestSolverLayoutAgreesWithBoard(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for size := 1; size <= maxSolverSize; size++ {
+		layout := newSolverLayout(size)
+		for game := 0; game < 500; game++ {
+			board := NewBoard(size)
+			position := layout.position(board)
+			for {
+				value, over := layout.result(position)
+				_, decided := board.DecidedWinner()
+				if over != (decided || board.IsFull()) {
+					t.Fatalf("%dx%d game %d: the solver ends the game %v, the board %v:\n%s", size, size, game, over, !over, board)
+				}
+				if over {
+					blueScore, redScore := board.Score()
+					want := empty
+					if blueScore > redScore {
+						want = blue
+					} else if redScore > blueScore {
+						want = red
+					}
+					if got := solverWinner(position.next, value); got != want {
+						t.Fatalf("%dx%d game %d: the solver scores a win for %s, the board for %s:\n%s", size, size, game, got, want, board)
+					}
+					break
+				}
+
+				var free []point
+				for y := 0; y < size; y++ {
+					for x := 0; x < size; x++ {
+						if board.At(x, y) == empty {
+							free = append(free, point{x, y})
+						}
+					}
+				}
+				p := free[random.Intn(len(free))]
+				board.Play(board.ToMove(), p.x, p.y)
+				layout.play(&position, p.y*size+p.x)
+				if want := layout.position(board); position != want {
+					t.Fatalf("%dx%d game %d: after %s the solver has another position than the board:\n%s",
+						size, size, game, formatVertex(p, size), board)
+				}
+			}
+		}
+	}
+}
+
+func TestSolverBotPlaysTheColorAsked(t *testing.T) {
+	bot := NewSolverBot()
+	board := NewBoard(3)
+	for _, color := range []cellState{red, blue} {
+		if m := bot.GenMove(board.Copy(), color); m.color != color || m.pass {
+			t.Errorf("asked for a %s move on the empty board, got %+v", color, m)
+		}
+	}
+}
+
+func TestSolverTableReplacement(t *testing.T) {
+	s := NewSolver()
+	s.table = make([]solverBucket, 1<<solverTableBits)
+	key := uint64(12345)
+	s.store(solverEntry{key: key, work: 100, value: 1})
+	if entry, ok := s.lookup(key); !ok || entry.value != 1 {
+		t.Fatalf("the entry stored is missing, got %+v", entry)
+	}
+
+	// Look for two more keys sharing the bucket
+	sameBucket := func(after uint64) uint64 {
+		for next := after + 1; ; next++ {
+			if s.bucket(next) == s.bucket(key) {
+				return next
+			}
+		}
+	}
+	other := sameBucket(key)
+	s.store(solverEntry{key: other, work: 10, value: -1})
+	third := sameBucket(other)
+	s.store(solverEntry{key: third, work: 20, value: 0})
+	if _, ok := s.lookup(key); !ok {
+		t.Error("a cheaper entry replaced the one that took the most work")
+	}
+	if _, ok := s.lookup(other); ok {
+		t.Error("the latest entry did not replace the previous one")
+	}
+	if _, ok := s.lookup(third); !ok {
+		t.Error("the latest entry is missing")
+	}
+}
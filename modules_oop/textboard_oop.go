@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// columnLetters names the columns as formatVertex does, without the letter I.
+const columnLetters = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
+// TextOptions selects how a position is written as text.
+type TextOptions struct {
+	coordinates bool // Column letters around the board and row numbers on both sides
+	unicode     bool // Write · ● ○ instead of . X O
+}
+
+// textSymbols maps the characters accepted in text positions to cell states.
+var textSymbols = map[rune]cellState{
+	'.': empty, '+': empty, '·': empty,
+	'X': blue, 'x': blue, '●': blue,
+	'O': red, 'o': red, '○': red,
+}
+
+// Text writes the position as rows of symbols, blue being X and red being O.
+func (b *Board) Text(opts TextOptions) string {
+	symbols := map[cellState]string{empty: ".", blue: "X", red: "O"}
+	if opts.unicode {
+		symbols = map[cellState]string{empty: "·", blue: "●", red: "○"}
+	}
+	numberWidth := len(fmt.Sprint(b.size))
+
+	var text strings.Builder
+	header := ""
+	if opts.coordinates {
+		letters := make([]string, b.size)
+		for x := range letters {
+			letters[x] = formatVertex(point{x, 0}, b.size)[:1]
+		}
+		header = strings.Repeat(" ", numberWidth+1) + strings.Join(letters, " ") + "\n"
+		text.WriteString(header)
+	}
+
+	for y := 0; y < b.size; y++ {
+		row := make([]string, b.size)
+		for x := range row {
+			row[x] = symbols[b.cells[x][y]]
+		}
+
+		if opts.coordinates {
+			fmt.Fprintf(&text, "%*d %s %d\n", numberWidth, b.size-y, strings.Join(row, " "), b.size-y)
+		} else {
+			text.WriteString(strings.Join(row, " ") + "\n")
+		}
+	}
+
+	text.WriteString(header)
+	return text.String()
+}
+
+// String writes the position without coordinates, handy when debugging.
+func (b *Board) String() string {
+	return b.Text(TextOptions{})
+}
+
+// ParseText reads a position written by Text, with or without coordinates and in either
+// symbol set. Symbols may be separated by spaces or not, blank lines are ignored. The dots
+// are set up without filling clusters, and blue is to move.
+func ParseText(text string) (*Board, error) {
+	var rows [][]cellState
+	for _, line := range strings.Split(text, "\n") {
+		// Drop the row numbers on both sides
+		line = strings.TrimFunc(line, func(r rune) bool {
+			return unicode.IsSpace(r) || unicode.IsDigit(r)
+		})
+		compact := strings.Join(strings.Fields(line), "")
+		if compact == "" || strings.HasPrefix(columnLetters, compact) {
+			continue // A blank line or the column letters
+		}
+
+		var row []cellState
+		for _, symbol := range compact {
+			state, ok := textSymbols[symbol]
+			if !ok {
+				return nil, fmt.Errorf("text board: unknown symbol %q", symbol)
+			}
+			row = append(row, state)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("text board: no rows found")
+	}
+	if len(rows) > maxBoardSize {
+		return nil, fmt.Errorf("text board: %d rows, boards have at most %d", len(rows), maxBoardSize)
+	}
+	for _, row := range rows {
+		if len(row) != len(rows) {
+			return nil, fmt.Errorf("text board: %d rows of %d cells, the board must be square", len(rows), len(row))
+		}
+	}
+
+	board := NewBoard(len(rows))
+	for y, row := range rows {
+		for x, state := range row {
+			if state != empty {
+				board.Setup(state, x, y)
+			}
+		}
+	}
+	return board, nil
+}
+
+// GameFromText reads a game written as SGF, or a position written as text, see ParseText.
+// The dots of a position are set up at the start of a new game.
+func GameFromText(text string) (*GameTree, int, GameInfo, error) {
+	if strings.HasPrefix(strings.TrimSpace(text), "(") {
+		return ReadSGF(strings.NewReader(text))
+	}
+
+	board, err := ParseText(text)
+	if err != nil {
+		return nil, 0, GameInfo{}, err
+	}
+	tree := NewGameTree()
+	for y := 0; y < board.size; y++ {
+		for x := 0; x < board.size; x++ {
+			if state := board.At(x, y); state != empty {
+				tree.root.setup = append(tree.root.setup, move{color: state, x: x, y: y})
+			}
+		}
+	}
+	return tree, board.size, defaultGameInfo(), nil
+}
+
+// Additional methods for handling text board functionalities
+// ...
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// textFormats are the ways Text can write a position, each read back by ParseText.
+var textFormats = []TextOptions{
+	{},
+	{coordinates: true},
+	{unicode: true},
+	{coordinates: true, unicode: true},
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	names, inputs := goldenInputs(t, "text", ".txt")
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			board, err := ParseText(inputs[name])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got strings.Builder
+			for _, opts := range textFormats {
+				text := board.Text(opts)
+				parsed, err := ParseText(text)
+				if err != nil {
+					t.Fatalf("%+v: %v", opts, err)
+				}
+				if !sameCells(parsed, board) {
+					t.Errorf("%+v: the position read back differs:\n%s", opts, parsed)
+				}
+				fmt.Fprintf(&got, "%+v\n%s\n", opts, text)
+			}
+			checkGolden(t, name, got.String())
+		})
+	}
+}
+
+// TestLargestBoard checks that every column of the largest board has a letter of its own,
+// which vertices and text boards read back.
+func TestLargestBoard(t *testing.T) {
+	board := NewBoard(maxBoardSize)
+	seen := make(map[string]bool)
+	for x := 0; x < maxBoardSize; x++ {
+		p := point{x, x}
+		board.Setup(blue, p.x, p.y)
+		vertex := formatVertex(p, maxBoardSize)
+		if seen[vertex[:1]] {
+			t.Errorf("column %d is named %s like another one", x, vertex[:1])
+		}
+		seen[vertex[:1]] = true
+		if got, err := parseVertex(vertex, maxBoardSize); err != nil || got != p {
+			t.Errorf("parseVertex(%q) = %v, %v, want %v", vertex, got, err, p)
+		}
+	}
+
+	text := board.Text(TextOptions{coordinates: true})
+	if !strings.HasPrefix(text, "   A B C D E F G H J") || !strings.Contains(text, "X 1\n") {
+		t.Errorf("unexpected coordinates:\n%s", text)
+	}
+	if parsed, err := ParseText(text); err != nil || !sameCells(parsed, board) {
+		t.Errorf("the %dx%d board read back differs: %v\n%s", maxBoardSize, maxBoardSize, err, parsed)
+	}
+}
+
+func TestParseTextErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"X O\nO",
+		". X\n. Z",
+		"X O .\nO X .",
+		strings.Repeat(strings.Repeat(".", maxBoardSize+1)+"\n", maxBoardSize+1),
+	} {
+		if board, err := ParseText(text); err == nil {
+			t.Errorf("ParseText(%q) = \n%s, want an error", text, board)
+		}
+	}
+}
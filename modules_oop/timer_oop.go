@@ -10,6 +10,7 @@ import (
 type Timer struct {
 	ticker           *time.Ticker
 	startTime        time.Time
+	stoppedAt        time.Duration // Time shown once the timer is stopped
 	timeElapsedLabel *widget.Label
 }
 
@@ -22,11 +23,17 @@ func NewTimer(label *widget.Label) *Timer {
 
 // Start begins or resumes the timer.
 func (t *Timer) Start() {
+	t.StartFrom(0)
+}
+
+// StartFrom starts the timer as if it had already been running for the given time.
+func (t *Timer) StartFrom(elapsed time.Duration) {
 	if t.ticker != nil {
 		return // Timer is already running
 	}
-	t.startTime = time.Now()
+	t.startTime = time.Now().Add(-elapsed)
 	t.ticker = time.NewTicker(time.Second)
+	t.timeElapsedLabel.SetText(fmt.Sprintf("Time: %v", elapsed.Round(time.Second)))
 
 	go func() {
 		for range t.ticker.C {
@@ -39,11 +46,20 @@ func (t *Timer) Start() {
 // Stop halts the timer.
 func (t *Timer) Stop() {
 	if t.ticker != nil {
+		t.stoppedAt = time.Since(t.startTime)
 		t.ticker.Stop()
 		t.ticker = nil
 	}
 }
 
+// Elapsed returns the time shown by the timer.
+func (t *Timer) Elapsed() time.Duration {
+	if t.ticker == nil {
+		return t.stoppedAt
+	}
+	return time.Since(t.startTime)
+}
+
 // Reset stops the current timer and starts it anew.
 func (t *Timer) Reset() {
 	t.Stop()
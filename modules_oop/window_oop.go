@@ -7,15 +7,23 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"image/color"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // GameWindow represents the main game window.
 type GameWindow struct {
 	window       fyne.Window
 	grid         *Grid
+	gameTree     *GameTree
+	gameInfo     GameInfo
 	timer        *Timer
 	musicPlayer  *MusicPlayer
 	windowWidth  int
@@ -28,8 +36,33 @@ type GameWindow struct {
 	gridSizeInput     *widget.Entry
 	backgroundImage   *canvas.Image
 	gameEndBanner     *fyne.Container
+	replayBar         *ReplayBar
+	treeView          *widget.Tree
+	commentLabel      *widget.Label
+	territoryCheck    *widget.Check
+	lifeCheck         *widget.Check
+	hintButton        *widget.Button
+	autosaveReported  bool // Whether a failed autosave was shown, later failures are only logged
+	// Computer players: an external GTP engine and bots, none when people play both colors
+	engine   *EnginePlayer
+	bots     map[cellState]Bot
+	players  map[cellState]string // Names chosen in the new game dialog
+	thinking any                  // Computer player choosing a move, taps are ignored meanwhile
+	hint     *MCTSBot             // Search of a hint in progress
+	library  *LibraryWindow
+	analysis *AnalysisWindow
+	// mu guards the game: the grid, the game tree and the players. Taps, navigation and menus
+	// hold it, and work done in the background takes it to hand its result back, see do.
+	mu sync.Mutex
+	// Set while the tree view follows the game, so that its selection does not navigate
+	followingTree atomic.Bool
 }
 
+const treePanelWidth = 160
+
+// botMoveDelay is the least time a bot takes for a move.
+const botMoveDelay = 300 * time.Millisecond
+
 func NewGameWindow(app fyne.App) *GameWindow {
 	mainWindow := app.NewWindow("Go in Go: the coolest version")
 
@@ -38,15 +71,20 @@ func NewGameWindow(app fyne.App) *GameWindow {
 		windowWidth:  500,
 		windowHeight: 500,
 		gridSize:     9,
+		gameInfo:     defaultGameInfo(),
 		// Initialize labels
 		blueDotCountLabel: widget.NewLabel("Blue Dots: 0"),
 		redDotCountLabel:  widget.NewLabel("Red Dots: 0"),
 		timeElapsedLabel:  widget.NewLabel("Time: 0s"),
 		// Initialize the gameEndBanner
 		gameEndBanner: createGameEndBanner(),
+		bots:          make(map[cellState]Bot),
+		players:       map[cellState]string{blue: humanPlayer, red: humanPlayer},
 		// Initialize gridSizeInput
 		gridSizeInput: widget.NewEntry(),
+		commentLabel:  widget.NewLabel(""),
 	}
+	gw.commentLabel.Wrapping = fyne.TextWrapWord
 
 	// Initialize Timer
 	gw.timer = NewTimer(gw.timeElapsedLabel)
@@ -57,9 +95,11 @@ func NewGameWindow(app fyne.App) *GameWindow {
 	// Configure gridSizeInput
 	gw.gridSizeInput.SetText(fmt.Sprintf("%d", gw.gridSize))
 	gw.gridSizeInput.OnSubmitted = func(value string) {
+		gw.mu.Lock()
+		defer gw.mu.Unlock()
 		newGridSize, err := strconv.Atoi(value)
-		if err != nil || newGridSize < 1 {
-			dialog.ShowError(fmt.Errorf("Invalid grid size"), gw.window)
+		if err != nil || newGridSize < 1 || newGridSize > maxBoardSize {
+			dialog.ShowError(fmt.Errorf("The grid size must be 1 to %d", maxBoardSize), gw.window)
 			gw.gridSizeInput.SetText(fmt.Sprintf("%d", gw.gridSize)) // Reset to current grid size
 			return
 		}
@@ -79,7 +119,8 @@ func (gw *GameWindow) Show() {
 
 func (gw *GameWindow) Configure() {
 	gw.window.SetTitle("Go in Go: the coolest version")
-	gw.window.Resize(fyne.NewSize(float32(gw.windowWidth), float32(gw.windowHeight)*1.1))
+	gw.window.Resize(fyne.NewSize(float32(gw.windowWidth)+treePanelWidth, float32(gw.windowHeight)*1.1))
+	gw.window.SetMainMenu(gw.createMainMenu())
 
 	// Initialize Music Player
 	gw.musicPlayer = NewMusicPlayer("../background.mp3") // Assuming you have a NewMusicPlayer function
@@ -97,9 +138,11 @@ func (gw *GameWindow) Configure() {
 	gw.gridSizeInput = widget.NewEntry()
 	gw.gridSizeInput.SetText(fmt.Sprintf("%d", gw.gridSize))
 	gw.gridSizeInput.OnSubmitted = func(value string) {
+		gw.mu.Lock()
+		defer gw.mu.Unlock()
 		newGridSize, err := strconv.Atoi(value)
-		if err != nil || newGridSize < 1 {
-			dialog.ShowError(fmt.Errorf("Invalid grid size"), gw.window)
+		if err != nil || newGridSize < 1 || newGridSize > maxBoardSize {
+			dialog.ShowError(fmt.Errorf("The grid size must be 1 to %d", maxBoardSize), gw.window)
 			gw.gridSizeInput.SetText(fmt.Sprintf("%d", gw.gridSize)) // Reset to current grid size
 			return
 		}
@@ -110,8 +153,23 @@ func (gw *GameWindow) Configure() {
 }
 
 func (gw *GameWindow) UpdateDotCounters() {
-	gw.blueDotCountLabel.SetText(fmt.Sprintf("Blue Dots: %d", *gw.grid.blueDotCount))
-	gw.redDotCountLabel.SetText(fmt.Sprintf("Red Dots: %d", *gw.grid.redDotCount))
+	blueText := fmt.Sprintf("Blue Dots: %d", gw.grid.board.Count(blue))
+	redText := fmt.Sprintf("Red Dots: %d", gw.grid.board.Count(red))
+
+	// Once the game is over, show the score, the cells nobody can take away any more.
+	// Before that, with the territory overlay on, show the estimated final score.
+	if gw.grid.gameOver {
+		blueScore, redScore := gw.grid.board.Score()
+		blueText += fmt.Sprintf(" (score %d)", blueScore)
+		redText += fmt.Sprintf(" (score %d)", redScore)
+	} else if gw.grid.showInfluence {
+		blueScore, redScore := gw.grid.board.ScoreEstimate()
+		blueText += fmt.Sprintf(" (~%d)", blueScore)
+		redText += fmt.Sprintf(" (~%d)", redScore)
+	}
+
+	gw.blueDotCountLabel.SetText(blueText)
+	gw.redDotCountLabel.SetText(redText)
 }
 
 func createGameEndBanner() *fyne.Container {
@@ -155,21 +213,27 @@ func (gw *GameWindow) RegenerateGrid(newGridSize int) {
 	gw.grid = NewGrid(gw.gridSize, 400/gw.gridSize, float32(gw.windowWidth)*0.1, float32(gw.windowHeight)*0.1, gw.UpdateDotCounters, gw.timer, gw)
 	gw.grid.DrawGrid()
 	gw.grid.onDotPlaced = gw.UpdateDotCounters
+	gw.grid.onMovePlayed = gw.RecordMove
+	gw.grid.onTurnEnded = gw.playComputerTurn
+	gw.grid.canPlay = func() bool { return gw.thinking == nil && !gw.computerPlays(gw.grid.board.ToMove()) }
+	gw.gameTree = NewGameTree()
+	gw.gameInfo = defaultGameInfo()
+	gw.treeView = gw.createTreeView()
+	if gw.replayBar != nil {
+		gw.replayBar.StopAutoplay()
+	}
+	gw.cancelHint()
+	gw.replayBar = NewReplayBar(gw)
 
-	resetButton := widget.NewButton("Go try again", func() {
-		// Reset the grid
-		for x := range gw.grid.cellStates {
-			for y := range gw.grid.cellStates[x] {
-				gw.grid.cellStates[x][y] = empty
-			}
-		}
-		gw.grid.dotsContainer.RemoveAll()
-		gw.grid.dotsContainer.Refresh()
+	resetButton := widget.NewButton("Go try again", gw.locked(func() {
+		// Reset the grid and forget all explored variations
+		gw.grid.Clear()
+		gw.gameTree = NewGameTree()
+		gw.gameInfo = defaultGameInfo()
+		gw.replayBar.Hide()
+		gw.refreshTreeView()
 
 		// Reset dot counters
-		*gw.grid.blueDotCount = 0
-		*gw.grid.redDotCount = 0
-		*gw.grid.dotCount = 0
 		gw.blueDotCountLabel.SetText("Blue Dots: 0")
 		gw.redDotCountLabel.SetText("Red Dots: 0")
 
@@ -184,7 +248,10 @@ func (gw *GameWindow) RegenerateGrid(newGridSize int) {
 
 		// Optionally, redraw the grid if needed
 		gw.grid.DrawGrid()
-	})
+
+		// An engine playing blue opens the new game
+		gw.playComputerTurn()
+	}))
 
 	// Load and set the background image
 	gw.backgroundImage = canvas.NewImageFromFile("../background.png")
@@ -201,7 +268,11 @@ func (gw *GameWindow) RegenerateGrid(newGridSize int) {
 	mainContainer := container.NewWithoutLayout()
 	mainContainer.Add(gw.backgroundImage)
 	mainContainer.Add(gw.grid.container)
+	mainContainer.Add(gw.grid.influence)
 	mainContainer.Add(gw.grid.dotsContainer) // Make sure dotsContainer is part of the main content
+	mainContainer.Add(gw.grid.life)
+	mainContainer.Add(gw.grid.ladder)
+	mainContainer.Add(gw.grid.hints)
 
 	// Layout for the top bar with the timer at the right of the dot counters
 	topBar := container.NewHBox(
@@ -213,22 +284,791 @@ func (gw *GameWindow) RegenerateGrid(newGridSize int) {
 		gw.timeElapsedLabel, // Timer label on the far right
 	)
 
+	gw.territoryCheck = widget.NewCheck("Territory", func(show bool) {
+		gw.mu.Lock()
+		defer gw.mu.Unlock()
+		gw.grid.SetInfluenceVisible(show)
+		gw.UpdateDotCounters()
+	})
+	gw.lifeCheck = widget.NewCheck("Life", func(show bool) {
+		gw.mu.Lock()
+		defer gw.mu.Unlock()
+		gw.grid.SetLifeVisible(show)
+	})
+	gw.hintButton = widget.NewButtonWithIcon("Hint", theme.SearchIcon(), gw.locked(gw.ShowHint))
+
+	// Buttons for moving through the game tree
+	navigationBar := container.NewHBox(
+		widget.NewButtonWithIcon("", theme.NavigateBackIcon(), gw.locked(func() { gw.navigate((*GameTree).Back) })),
+		widget.NewButtonWithIcon("", theme.NavigateNextIcon(), gw.locked(func() { gw.navigate((*GameTree).Forward) })),
+		widget.NewButtonWithIcon("Variation", theme.MoveUpIcon(), gw.locked(func() { gw.navigate((*GameTree).PreviousVariation) })),
+		widget.NewButtonWithIcon("Variation", theme.MoveDownIcon(), gw.locked(func() { gw.navigate((*GameTree).NextVariation) })),
+		widget.NewButtonWithIcon("Main line", theme.HomeIcon(), gw.locked(func() {
+			gw.gameTree.GoToMainLine()
+			gw.ReplayGameTree()
+		})),
+		layout.NewSpacer(),
+		widget.NewButtonWithIcon("Replay", theme.MediaReplayIcon(), gw.locked(gw.replayBar.Show)),
+		widget.NewButton("Ladder", gw.locked(gw.ShowLadders)),
+		gw.hintButton,
+		gw.territoryCheck,
+		gw.lifeCheck,
+	)
+
+	// The tree panel sits on the right of the board
+	treePanel := container.NewBorder(widget.NewLabel("Moves"), gw.commentLabel, nil, nil, gw.treeView)
+
 	// Combine the top bar with the main container
 	// Use a VBox layout to position the banner in the middle vertically
+	sidePanel := container.NewGridWrap(fyne.NewSize(treePanelWidth, float32(gw.windowHeight)), treePanel)
 	content := container.NewVBox(
-		container.NewBorder(topBar, nil, nil, nil, mainContainer),
+		container.NewBorder(container.NewVBox(topBar, navigationBar, gw.replayBar.container), nil, nil, sidePanel, mainContainer),
 		gw.gameEndBanner,
 	)
 
 	// Set the content of the window
 	gw.window.SetContent(content)
+
+	gw.playComputerTurn()
+}
+
+// locked wraps a callback of the window so that it runs holding the game lock.
+func (gw *GameWindow) locked(f func()) func() {
+	return func() {
+		gw.do(f)
+	}
+}
+
+// do runs f holding the game lock. Computer players and other work done in the background
+// hand their results back through it, so they are applied between the user's actions.
+func (gw *GameWindow) do(f func()) {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	f()
+}
+
+// RecordMove adds a move played on the grid to the game tree.
+// Playing after stepping back creates a new variation instead of discarding the old one.
+func (gw *GameWindow) RecordMove(m move) {
+	node := gw.gameTree.Play(m)
+	if node.playedAt.IsZero() {
+		// Keep the first timing of a move played again after going back
+		node.playedAt = time.Now()
+		node.clock = gw.timer.Elapsed()
+	}
+	gw.gameInfo.result = "" // A recorded result no longer holds once the game goes on
+	gw.refreshTreeView()
+	gw.Autosave()
+}
+
+// Autosave writes the game, the clock, the overlay settings and the players to the autosave file.
+// A game without moves is not saved, so the unfinished game kept there is offered again
+// until the first move of the new one.
+func (gw *GameWindow) Autosave() {
+	if root := gw.gameTree.root; len(root.children) == 0 && len(root.setup) == 0 {
+		return
+	}
+	path, err := autosavePath()
+	if err != nil {
+		fyne.LogError("Could not find the autosave directory", err)
+		return
+	}
+
+	players := map[cellState]string{blue: humanPlayer, red: humanPlayer}
+	for color, bot := range gw.bots {
+		if bot != nil {
+			players[color] = gw.players[color]
+		}
+	}
+	var engine *EngineConfig
+	if gw.engine != nil {
+		engine = &gw.engine.config
+	}
+	save, err := newAutosave(gw.gameTree, gw.gridSize, gw.gameInfo, gw.timer.Elapsed(), gw.grid.showInfluence, gw.grid.showLife, players, engine)
+	if err == nil {
+		err = writeAutosave(path, save)
+	}
+	if err != nil {
+		fyne.LogError("Could not autosave the game", err)
+		if !gw.autosaveReported {
+			gw.autosaveReported = true
+			dialog.ShowError(fmt.Errorf("Could not autosave the game: %w", err), gw.window)
+		}
+	}
+}
+
+// OfferResume asks whether to continue the game left unfinished in the autosave file, with
+// the bots and the engine that played it.
+func (gw *GameWindow) OfferResume(save autosave) {
+	if save.Finished || save.Moves == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Continue the unfinished %dx%d game from %s?", save.Size, save.Size, save.SavedAt.Format("Jan 2, 15:04"))
+	dialog.ShowConfirm("Resume game", message, func(resume bool) {
+		if !resume {
+			return
+		}
+
+		tree, info, err := save.restore()
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		gw.do(func() {
+			gw.LoadGame(tree, save.Size, info)
+			gw.timer.Stop()
+			gw.timer.StartFrom(save.elapsed())
+
+			gw.players = save.players()
+			gw.SetBots(NewBot(gw.players[blue]), NewBot(gw.players[red]))
+			if save.Engine != nil {
+				gw.StartEngine(*save.Engine)
+			}
+		})
+		// The checks take the lock themselves
+		gw.territoryCheck.SetChecked(save.ShowTerritory)
+		gw.lifeCheck.SetChecked(save.ShowLife)
+	}, gw.window)
+}
+
+// ReplayGameTree redraws the grid at the current node of the game tree, and lets the computer
+// play if it has the move there.
+func (gw *GameWindow) ReplayGameTree() {
+	gw.gameEndBanner.Hide()
+	gw.grid.Clear()
+	for _, node := range gw.gameTree.Path() {
+		if node.parent != nil {
+			gw.grid.ApplyMove(node.move)
+		}
+		for _, stone := range node.setup {
+			gw.grid.SetupDot(stone.color, stone.x, stone.y)
+		}
+	}
+	gw.UpdateDotCounters()
+	gw.refreshTreeView()
+	gw.playComputerTurn()
+}
+
+// navigate applies a game tree step and redraws the grid if the position changed.
+func (gw *GameWindow) navigate(step func(t *GameTree) bool) {
+	if step(gw.gameTree) {
+		gw.ReplayGameTree()
+	}
+}
+
+func (gw *GameWindow) createTreeView() *widget.Tree {
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			node := gw.treeNode(id)
+			if node == nil {
+				return nil
+			}
+
+			// The root and the variations list their continuation, main line moves
+			// list the alternatives branching off at them
+			var nodes []*GameNode
+			if node.parent == nil || node.isVariation() {
+				if len(node.children) > 0 {
+					nodes = node.children[0].line()
+				}
+			} else {
+				nodes = node.parent.children[1:]
+			}
+
+			ids := make([]widget.TreeNodeID, len(nodes))
+			for i, child := range nodes {
+				ids[i] = strconv.Itoa(child.id)
+			}
+			return ids
+		},
+		func(id widget.TreeNodeID) bool {
+			node := gw.treeNode(id)
+			if node == nil || node.parent == nil {
+				return true
+			}
+			if node.isVariation() {
+				return len(node.children) > 0
+			}
+			return len(node.parent.children) > 1
+		},
+		func(_ bool) fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TreeNodeID, _ bool, item fyne.CanvasObject) {
+			node := gw.treeNode(id)
+			if node == nil || node.parent == nil {
+				return
+			}
+			if node.move.pass {
+				item.(*widget.Label).SetText(fmt.Sprintf("%d. %s pass", node.depth(), node.move.color))
+				return
+			}
+			item.(*widget.Label).SetText(fmt.Sprintf("%d. %s (%d, %d)", node.depth(), node.move.color, node.move.x+1, node.move.y+1))
+		},
+	)
+
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		if gw.followingTree.Load() {
+			return
+		}
+		gw.mu.Lock()
+		defer gw.mu.Unlock()
+		node := gw.treeNode(id)
+		if node == nil || node == gw.gameTree.current {
+			return
+		}
+		gw.gameTree.GoTo(node)
+		gw.ReplayGameTree()
+	}
+
+	return tree
+}
+
+// treeNode maps a tree view id to its game tree node, the empty id being the root.
+func (gw *GameWindow) treeNode(id widget.TreeNodeID) *GameNode {
+	if id == "" {
+		return gw.gameTree.root
+	}
+	nodeID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil
+	}
+	return gw.gameTree.nodes[nodeID]
+}
+
+func (gw *GameWindow) refreshTreeView() {
+	gw.commentLabel.SetText(gw.gameTree.current.comment)
+	gw.replayBar.Update()
+	if gw.analysis != nil {
+		gw.analysis.Update()
+	}
+	gw.treeView.Refresh()
+	gw.treeView.OpenAllBranches()
+	gw.followingTree.Store(true)
+	defer gw.followingTree.Store(false)
+	if current := gw.gameTree.current; current.parent != nil {
+		gw.treeView.Select(strconv.Itoa(current.id))
+		gw.treeView.ScrollTo(strconv.Itoa(current.id))
+	} else {
+		gw.treeView.UnselectAll()
+	}
+}
+
+// createMainMenu builds the window menu with the game file actions.
+func (gw *GameWindow) createMainMenu() *fyne.MainMenu {
+	return fyne.NewMainMenu(
+		fyne.NewMenu("File",
+			fyne.NewMenuItem("New game...", gw.locked(gw.ShowNewGameDialog)),
+			fyne.NewMenuItem("Library...", gw.locked(gw.ShowLibrary)),
+			fyne.NewMenuItem("Open game...", gw.locked(gw.ShowOpenDialog)),
+			fyne.NewMenuItem("Save game...", gw.locked(gw.ShowSaveDialog)),
+			fyne.NewMenuItem("Export diagram...", gw.locked(gw.ShowExportDiagramDialog)),
+			fyne.NewMenuItem("Export move list...", gw.locked(gw.ShowExportMoveListDialog)),
+		),
+		fyne.NewMenu("Edit",
+			fyne.NewMenuItem("Copy game as SGF", gw.locked(gw.CopyGame)),
+			fyne.NewMenuItem("Copy position as text", gw.locked(gw.CopyPosition)),
+			fyne.NewMenuItem("Paste game or position", gw.locked(gw.Paste)),
+		),
+		fyne.NewMenu("Engine",
+			fyne.NewMenuItem("Play against engine...", gw.locked(gw.ShowEngineDialog)),
+			fyne.NewMenuItem("Stop engine", gw.locked(gw.StopEngine)),
+		),
+	)
+}
+
+// ShowLibrary opens the window listing the saved games, or brings it to the front.
+func (gw *GameWindow) ShowLibrary() {
+	if gw.library == nil {
+		gw.library = NewLibraryWindow(fyne.CurrentApp(), gw)
+		gw.library.window.SetOnClosed(gw.locked(func() { gw.library = nil }))
+	}
+	gw.library.Show()
+}
+
+// ShowAnalysis opens a window analysing the variation shown, replacing an earlier analysis.
+func (gw *GameWindow) ShowAnalysis() {
+	if gw.analysis != nil {
+		// The lock is held already, the window is replaced here
+		gw.analysis.window.SetOnClosed(gw.analysis.Stop)
+		gw.analysis.window.Close()
+	}
+	analysis := NewAnalysisWindow(fyne.CurrentApp(), gw)
+	analysis.window.SetOnClosed(func() {
+		analysis.Stop()
+		gw.do(func() {
+			if gw.analysis == analysis {
+				gw.analysis = nil
+			}
+		})
+	})
+	gw.analysis = analysis
+	analysis.Show()
+}
+
+// CopyGame puts the game with all its variations on the clipboard as SGF.
+func (gw *GameWindow) CopyGame() {
+	var sgf strings.Builder
+	if err := WriteSGF(&sgf, gw.gameTree, gw.gridSize, gw.gameInfo); err != nil {
+		dialog.ShowError(err, gw.window)
+		return
+	}
+	gw.window.Clipboard().SetContent(sgf.String())
+}
+
+// CopyPosition puts the position shown on the clipboard as a text diagram with coordinates.
+func (gw *GameWindow) CopyPosition() {
+	gw.window.Clipboard().SetContent(gw.grid.board.Text(TextOptions{coordinates: true}))
+}
+
+// Paste loads an SGF game or a text diagram from the clipboard, see GameFromText.
+func (gw *GameWindow) Paste() {
+	tree, size, info, err := GameFromText(gw.window.Clipboard().Content())
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("The clipboard holds no game or position: %w", err), gw.window)
+		return
+	}
+	gw.LoadGame(tree, size, info)
+}
+
+// SaveGame writes the game with all its variations to an SGF file.
+func (gw *GameWindow) SaveGame(path string) error {
+	return SaveSGF(path, gw.gameTree, gw.gridSize, gw.gameInfo)
+}
+
+// ShowSaveDialog asks for a file and saves the game there as SGF.
+func (gw *GameWindow) ShowSaveDialog() {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		if writer == nil {
+			return // The dialog was cancelled
+		}
+		defer writer.Close()
+
+		gw.mu.Lock()
+		defer gw.mu.Unlock()
+		if err := WriteSGF(writer, gw.gameTree, gw.gridSize, gw.gameInfo); err != nil {
+			dialog.ShowError(err, gw.window)
+		}
+	}, gw.window)
+	saveDialog.SetFileName("game.sgf")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".sgf"}))
+	saveDialog.Show()
+}
+
+// LoadGame replaces the current game with a loaded one, resizing the grid to its board.
+// The game starts at its first position, the navigation buttons step through the moves.
+// The game is loaded for review, so the engine and the bots stop playing.
+func (gw *GameWindow) LoadGame(tree *GameTree, size int, info GameInfo) {
+	gw.StopEngine()
+	gw.setBots(nil, nil)
+	gw.gridSizeInput.SetText(fmt.Sprintf("%d", size))
+	gw.RegenerateGrid(size)
+	gw.gameTree = tree
+	gw.gameInfo = info
+	gw.ReplayGameTree()
+	gw.replayBar.Show()
+}
+
+// OpenGame loads an SGF file, see LoadGame.
+func (gw *GameWindow) OpenGame(path string) error {
+	tree, size, info, err := LoadSGF(path)
+	if err != nil {
+		return err
+	}
+	gw.LoadGame(tree, size, info)
+	return nil
+}
+
+// ShowOpenDialog asks for an SGF file and loads the game from it.
+func (gw *GameWindow) ShowOpenDialog() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		if reader == nil {
+			return // The dialog was cancelled
+		}
+		defer reader.Close()
+
+		tree, size, info, err := ReadSGF(reader)
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		gw.do(func() { gw.LoadGame(tree, size, info) })
+	}, gw.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".sgf"}))
+	openDialog.Show()
+}
+
+// ShowExportDiagramDialog asks what to draw, then exports the position as a PNG or SVG file.
+func (gw *GameWindow) ShowExportDiagramDialog() {
+	coordinatesCheck := widget.NewCheck("", nil)
+	coordinatesCheck.SetChecked(true)
+	moveNumbersCheck := widget.NewCheck("", nil)
+	lastMoveCheck := widget.NewCheck("", nil)
+	lastMoveCheck.SetChecked(true)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Coordinates", coordinatesCheck),
+		widget.NewFormItem("Move numbers", moveNumbersCheck),
+		widget.NewFormItem("Last move marker", lastMoveCheck),
+	}
+	dialog.ShowForm("Export diagram", "Export", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		opts := DiagramOptions{
+			coordinates: coordinatesCheck.Checked,
+			moveNumbers: moveNumbersCheck.Checked,
+			lastMove:    lastMoveCheck.Checked,
+		}
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, gw.window)
+				return
+			}
+			if writer == nil {
+				return // The dialog was cancelled
+			}
+			defer writer.Close()
+
+			gw.mu.Lock()
+			d := newDiagram(gw.gameTree, gw.gameTree.current, gw.gridSize)
+			gw.mu.Unlock()
+			if strings.EqualFold(writer.URI().Extension(), ".svg") {
+				err = d.WriteSVG(writer, opts)
+			} else {
+				err = d.WritePNG(writer, opts)
+			}
+			if err != nil {
+				dialog.ShowError(err, gw.window)
+			}
+		}, gw.window)
+		saveDialog.SetFileName("diagram.png")
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".svg"}))
+		saveDialog.Show()
+	}, gw.window)
+}
+
+// ShowExportMoveListDialog saves the moves of the variation shown as CSV or JSON,
+// chosen by the file extension.
+func (gw *GameWindow) ShowExportMoveListDialog() {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		if writer == nil {
+			return // The dialog was cancelled
+		}
+		defer writer.Close()
+
+		gw.mu.Lock()
+		records := NewMoveList(gw.gameTree, gw.gridSize)
+		gw.mu.Unlock()
+		if strings.EqualFold(writer.URI().Extension(), ".json") {
+			err = WriteMoveListJSON(writer, records)
+		} else {
+			err = WriteMoveListCSV(writer, records)
+		}
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+		}
+	}, gw.window)
+	saveDialog.SetFileName("moves.csv")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json"}))
+	saveDialog.Show()
+}
+
+// ShowEngineDialog asks for a GTP engine and the color it plays, then starts it.
+// The settings are kept for the next time.
+func (gw *GameWindow) ShowEngineDialog() {
+	configPath, err := engineConfigPath()
+	if err != nil {
+		dialog.ShowError(err, gw.window)
+		return
+	}
+	config := readEngineConfig(configPath)
+
+	pathEntry := widget.NewEntry()
+	pathEntry.SetPlaceHolder("/usr/local/bin/gnugo")
+	pathEntry.SetText(config.Path)
+	argumentsEntry := widget.NewEntry()
+	argumentsEntry.SetPlaceHolder("--mode gtp")
+	argumentsEntry.SetText(config.Arguments)
+	colorSelect := widget.NewSelect([]string{blue.String(), red.String()}, nil)
+	colorSelect.SetSelected(config.Color)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Engine", pathEntry),
+		widget.NewFormItem("Arguments", argumentsEntry),
+		widget.NewFormItem("Engine plays", colorSelect),
+	}
+	dialog.ShowForm("Play against engine", "Start", "Cancel", items, func(confirmed bool) {
+		if !confirmed || pathEntry.Text == "" {
+			return
+		}
+		config := EngineConfig{Path: pathEntry.Text, Arguments: argumentsEntry.Text, Color: colorSelect.Selected}
+		if err := writeConfigFile(configPath, config); err != nil {
+			fyne.LogError("Could not save the engine settings", err)
+		}
+		gw.do(func() { gw.StartEngine(config) })
+	}, gw.window)
+}
+
+// StartEngine launches an engine in the background and lets it play its color,
+// replacing the engine played so far.
+func (gw *GameWindow) StartEngine(config EngineConfig) {
+	gw.StopEngine()
+	size, komi := gw.gridSize, gw.gameInfo.komi
+	go func() {
+		engine, err := NewEnginePlayer(config, size, komi)
+		if err != nil {
+			dialog.ShowError(err, gw.window)
+			return
+		}
+		gw.do(func() {
+			gw.StopEngine() // Another engine may have been started meanwhile
+			gw.engine = engine
+			delete(gw.bots, engine.color) // The engine takes over from a bot
+			gw.playComputerTurn()
+		})
+	}()
+}
+
+// StopEngine closes the engine, people play both colors again.
+func (gw *GameWindow) StopEngine() {
+	if gw.engine == nil {
+		return
+	}
+	engine := gw.engine
+	gw.engine = nil
+	if gw.thinking == engine {
+		gw.thinking = nil
+	}
+	go engine.Close() // Closing waits for the engine, which may still be thinking
+}
+
+// SetBots chooses the bot playing each color, a nil bot leaving the color to a human.
+// A bot takes over from the engine when they are given the same color.
+func (gw *GameWindow) SetBots(blueBot, redBot Bot) {
+	gw.setBots(blueBot, redBot)
+	gw.playComputerTurn()
+}
+
+// setBots replaces the bots without letting them play yet.
+func (gw *GameWindow) setBots(blueBot, redBot Bot) {
+	if _, ok := gw.thinking.(Bot); ok {
+		gw.thinking = nil // The move of a replaced bot is dropped
+	}
+	gw.bots = map[cellState]Bot{blue: blueBot, red: redBot}
+	if gw.engine != nil && gw.bots[gw.engine.color] != nil {
+		gw.StopEngine()
+	}
+}
+
+// ShowNewGameDialog asks for the board size and who plays each color, then starts a new game.
+// The choices are offered again the next time.
+func (gw *GameWindow) ShowNewGameDialog() {
+	sizeEntry := widget.NewEntry()
+	sizeEntry.SetText(fmt.Sprintf("%d", gw.gridSize))
+	sizeEntry.Validator = func(value string) error {
+		if size, err := strconv.Atoi(value); err != nil || size < 1 || size > maxBoardSize {
+			return fmt.Errorf("The grid size must be 1 to %d", maxBoardSize)
+		}
+		return nil
+	}
+	blueSelect := widget.NewSelect(playerNames, nil)
+	blueSelect.SetSelected(gw.players[blue])
+	redSelect := widget.NewSelect(playerNames, nil)
+	redSelect.SetSelected(gw.players[red])
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Board size", sizeEntry),
+		widget.NewFormItem("Blue", blueSelect),
+		widget.NewFormItem("Red", redSelect),
+	}
+	dialog.ShowForm("New game", "Start", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		gw.mu.Lock()
+		defer gw.mu.Unlock()
+		size, _ := strconv.Atoi(sizeEntry.Text) // Checked by the validator
+		gw.players = map[cellState]string{blue: blueSelect.Selected, red: redSelect.Selected}
+
+		// The new bots are in place before the new grid lets the computer open the game
+		gw.setBots(NewBot(blueSelect.Selected), NewBot(redSelect.Selected))
+		gw.gridSizeInput.SetText(sizeEntry.Text)
+		gw.RegenerateGrid(size)
+	}, gw.window)
+}
+
+// playComputerTurn lets the engine or the bot of the color to move play, without blocking
+// the window. Taps are ignored until the move is played. Autoplay only shows the moves made.
+func (gw *GameWindow) playComputerTurn() {
+	if gw.thinking != nil || gw.grid.gameOver || gw.replayBar.autoplay != nil {
+		return
+	}
+
+	color := gw.grid.board.ToMove()
+	if gw.engine != nil && gw.engine.color == color {
+		gw.playEngineTurn(gw.engine)
+	} else if bot := gw.bots[color]; bot != nil {
+		gw.playBotTurn(bot, color)
+	}
+}
+
+// computerPlays reports whether the engine or a bot plays the given color, so taps do not.
+func (gw *GameWindow) computerPlays(color cellState) bool {
+	return gw.engine != nil && gw.engine.color == color || gw.bots[color] != nil
+}
+
+// playBotTurn asks a bot for its move on a copy of the board. Quick moves are held back
+// a little so that games between bots can be followed.
+func (gw *GameWindow) playBotTurn(bot Bot, color cellState) {
+	gw.thinking = bot
+	node, board := gw.gameTree.current, gw.grid.board.Copy()
+	go func() {
+		start := time.Now()
+		m := bot.GenMove(board, color)
+		time.Sleep(botMoveDelay - time.Since(start))
+
+		gw.do(func() {
+			if gw.thinking != bot {
+				return // The bot was replaced while thinking
+			}
+			gw.thinking = nil
+			if node != gw.gameTree.current {
+				// Another position is shown now and the move is out of date, the computer may
+				// have the move there
+				gw.playComputerTurn()
+				return
+			}
+			gw.grid.PlayMove(m)
+		})
+	}()
+}
+
+// playEngineTurn asks the engine for its move. An engine that fails or times out is stopped.
+func (gw *GameWindow) playEngineTurn(engine *EnginePlayer) {
+	gw.thinking = engine
+	node, board := gw.gameTree.current, gw.grid.board.Copy()
+	plays := enginePlays(node, gw.gridSize)
+	go func() {
+		m, resigned, err := engine.GenMove(board, plays)
+		gw.do(func() {
+			if gw.thinking != engine {
+				return // The engine was stopped while thinking
+			}
+			gw.thinking = nil
+			if node != gw.gameTree.current {
+				// Another position is shown now and the move is out of date, the computer may
+				// have the move there
+				gw.playComputerTurn()
+				return
+			}
+
+			switch {
+			case err != nil:
+				gw.StopEngine()
+				dialog.ShowError(fmt.Errorf("Stopped playing against the engine: %w", err), gw.window)
+			case resigned:
+				gw.grid.gameOver = true
+				gw.timer.Stop()
+				gw.gameEndBanner.Show()
+				gw.gameInfo.result = gtpColor(opponent(engine.color)) + "+R"
+				gw.Autosave()
+				dialog.ShowInformation("Resignation", fmt.Sprintf("%s resigns", engine.color), gw.window)
+			default:
+				gw.grid.PlayMove(m)
+			}
+		})
+	}()
+}
+
+// ShowLadders reads out the ladders of all groups in atari and reports how they end.
+func (gw *GameWindow) ShowLadders() {
+	results := gw.grid.ShowLadders()
+	if len(results) == 0 {
+		dialog.ShowInformation("Ladder", "No group is in atari", gw.window)
+		return
+	}
+	dialog.ShowInformation("Ladder", strings.Join(results, "\n"), gw.window)
+}
+
+// ShowHint searches the current position in the background and rings the best moves for the
+// player to move without playing them. The hint is dropped if the position changes meanwhile.
+func (gw *GameWindow) ShowHint() {
+	if gw.grid.gameOver {
+		dialog.ShowInformation("Hint", "The game is over", gw.window)
+		return
+	}
+
+	if gw.hint != nil {
+		return // Still searching
+	}
+
+	bot, node, board := NewMCTSBot(hintMCTSConfig), gw.gameTree.current, gw.grid.board.Copy()
+	gw.hint = bot
+	gw.hintButton.Disable()
+	go func() {
+		candidates := bot.Candidates(board, board.ToMove())
+		gw.do(func() {
+			if gw.hint != bot {
+				return // Cancelled with the grid it was for
+			}
+			gw.hint = nil
+			gw.hintButton.Enable()
+			if node != gw.gameTree.current {
+				return // Another position is shown now
+			}
+			if len(candidates) == 0 {
+				dialog.ShowInformation("Hint", "There is no move to suggest", gw.window)
+				return
+			}
+			gw.grid.ShowHints(candidates[:min(hintCount, len(candidates))])
+		})
+	}()
+}
+
+// cancelHint stops the search of a hint, which is then dropped.
+func (gw *GameWindow) cancelHint() {
+	if gw.hint == nil {
+		return
+	}
+	gw.hint.Cancel()
+	gw.hint = nil
+	if gw.hintButton != nil {
+		gw.hintButton.Enable()
+	}
 }
 
 // Cleanup performs any necessary cleanup tasks for the GameWindow.
 func (gw *GameWindow) Cleanup() {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
 	if gw.timer != nil {
 		gw.timer.Stop()
 	}
+	gw.cancelHint()
+	if gw.grid != nil {
+		gw.Autosave() // Keep the game when the window is closed
+	}
+	if gw.engine != nil {
+		gw.engine.Close()
+	}
+	if gw.analysis != nil {
+		gw.analysis.Stop()
+	}
 	if gw.musicPlayer != nil {
 		gw.musicPlayer.Stop()
 	}